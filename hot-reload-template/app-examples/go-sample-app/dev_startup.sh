@@ -15,7 +15,7 @@
 #   1. Stops the currently running application process
 #   2. Runs 'go mod tidy' if dependencies changed (to update go.sum)
 #   3. Rebuilds the application binary
-#   4. Starts the new binary on port 8080
+#   4. Starts the new binary on $PORT (8080 by default)
 #
 # WHY IT'S NEEDED:
 # In a containerized development environment (like DigitalOcean App Platform), this
@@ -33,6 +33,7 @@
 #   - Initializes by running 'go mod tidy' and calculating initial hashes
 #   - Implements hard rebuild on go mod errors
 #   - Starts the Go application server
+#   - Reports build failures to /dev_health via dev-health-report
 #   - Enters a monitoring loop that checks for changes every 2 seconds
 #   - Automatically handles process cleanup and restart when changes are detected
 #
@@ -118,10 +119,26 @@ refresh_deps_if_changed() {
   return 1
 }
 
+# Report build results to dev-health-server (no-op outside the dev container)
+report_health() {
+  if command -v dev-health-report >/dev/null 2>&1; then
+    dev-health-report "$@" || true
+  fi
+}
+
 start_server() {
   echo "Starting Go app..."
-  # Build first, then run the binary directly for better process control
-  go build -o /tmp/go-app . 
+  # Build first, then run the binary directly for better process control.
+  # On a build error keep watching, so the next push can fix it.
+  local build_output
+  if ! build_output=$(go build -o /tmp/go-app . 2>&1); then
+    echo "$build_output"
+    echo "Build failed. Waiting for changes..."
+    report_health go-build fail "$(echo "$build_output" | tail -n 5)"
+    SERVER_PID=""
+    return 0
+  fi
+  report_health go-build pass "Built at $(date -u +%H:%M:%SZ)"
   /tmp/go-app &
   SERVER_PID=$!
   echo "Go app started with PID: $SERVER_PID"
@@ -136,12 +153,9 @@ stop_server() {
     sleep 1
     kill -9 "$SERVER_PID" >/dev/null 2>&1 || true
   fi
-  # Kill any go-app binary that might be running
+  # Kill any go-app binary that might be running. Never kill by port: with
+  # ENABLE_WELCOME_PROXY the welcome page proxy owns 8080 and the app is on $PORT.
   pkill -9 -f "/tmp/go-app" >/dev/null 2>&1 || true
-  # Also try to kill anything on port 8080 using fuser (more reliable than lsof)
-  fuser -k 8080/tcp >/dev/null 2>&1 || true
-  # Fallback to lsof if fuser not available
-  lsof -ti:8080 | xargs kill -9 >/dev/null 2>&1 || true
   sleep 1
   echo "Stop complete"
 }
@@ -35,7 +35,7 @@
 #   - Generates a helper script (.dev_run.sh) that nodemon will execute
 #   - Launches nodemon to watch package.json and execute .dev_run.sh on changes
 #   - The helper script checks if package.json changed, reinstalls if needed, and
-#     starts the Next.js dev server on port 8080
+#     starts the Next.js dev server on $PORT (8080 by default)
 #   - Implements hard rebuild on npm install errors
 #
 # The script runs continuously, with nodemon handling the process lifecycle and
@@ -146,7 +146,7 @@ if [ "$current" != "$previous" ]; then
 else
   echo "package.json unchanged. Skipping npm install."
 fi
-exec npm run dev -- --hostname 0.0.0.0 --port 8080
+exec npm run dev -- --hostname 0.0.0.0 --port "${PORT:-8080}"
 RUN
 chmod +x .dev_run.sh
 
@@ -120,7 +120,7 @@ chmod +x .dev_run.sh
 
 echo ""
 echo "Starting nodemon to watch for changes..."
-echo "App will be available on http://0.0.0.0:8080"
+echo "App will be available on http://0.0.0.0:${PORT:-8080}"
 echo ""
 
 # Start nodemon to watch package.json and rerun .dev_run.sh
@@ -37,7 +37,7 @@
 #   - Implements hard rebuild on uv sync errors
 #   - Creates a hash file (.deps_hash) to track dependency file state
 #   - Starts a background process that monitors pyproject.toml and uv.lock
-#   - Enters a main loop that runs uvicorn on port 8080 with --reload enabled
+#   - Enters a main loop that runs uvicorn on $PORT (8080 by default) with --reload enabled
 #   - When the watcher detects dependency changes, it kills uvicorn, triggering
 #     the main loop to restart it with the updated dependencies
 #
@@ -150,7 +150,7 @@ trap cleanup EXIT INT TERM
 # Main loop: uvicorn runs, watcher kills it when deps change, loop restarts it
 while true; do
   echo "Starting uvicorn..."
-  uv run uvicorn main:app --host 0.0.0.0 --port 8080 --reload &
+  uv run uvicorn main:app --host 0.0.0.0 --port "${PORT:-8080}" --reload &
   UVICORN_PID=$!
   echo "Uvicorn started (PID: $UVICORN_PID)"
 
@@ -158,11 +158,12 @@ echo "=========================================="
 echo "Starting Rails Server"
 echo "=========================================="
 echo "  Environment: development"
-echo "  Port: 8080"
+echo "  Port: ${PORT:-8080}"
 echo "  Hot-reload: enabled"
 echo ""
 
 # Start Rails server
 # -b 0.0.0.0: Bind to all interfaces (required for container access)
-# -p 8080: Use port 8080 (App Platform standard)
-exec bundle exec rails server -b 0.0.0.0 -p 8080
+# -p: $PORT, 8080 (the App Platform standard) unless the welcome page proxy
+#     moved the app to another port
+exec bundle exec rails server -b 0.0.0.0 -p "${PORT:-8080}"
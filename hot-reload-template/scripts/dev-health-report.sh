@@ -0,0 +1,97 @@
+#!/usr/bin/env bash
+# Dev Health Report
+# Writes a status file that dev-health-server merges into /dev_health as a check,
+# so scripts can report problems without running their own HTTP server
+#
+# USAGE:
+#   dev-health-report [--ttl SECONDS] [--critical] NAME pass|warn|fail [MESSAGE]
+#   dev-health-report --clear NAME
+#
+# With --ttl, the check turns into a failure if it is not reported again
+# within SECONDS (use this for anything that should report periodically).
+
+set -euo pipefail
+
+DROPIN_DIR="${DEV_HEALTH_DROPIN_DIR:-/tmp/dev-health.d}"
+
+usage() {
+    echo "Usage: $0 [--ttl SECONDS] [--critical] NAME pass|warn|fail [MESSAGE]" >&2
+    echo "       $0 --clear NAME" >&2
+    exit 1
+}
+
+# Escape a string for use inside a JSON string literal
+json_escape() {
+    local s="$1"
+    s="${s//\\/\\\\}"
+    s="${s//\"/\\\"}"
+    s="${s//$'\n'/\\n}"
+    s="${s//$'\r'/\\r}"
+    s="${s//$'\t'/\\t}"
+    # Drop any remaining control characters
+    printf '%s' "$s" | tr -d '\000-\037'
+}
+
+# File names are derived from the check name; keep them to a safe character set
+file_for() {
+    local safe
+    safe=$(printf '%s' "$1" | tr -c 'A-Za-z0-9._-' '_')
+    echo "$DROPIN_DIR/$safe.json"
+}
+
+main() {
+    local ttl=0
+    local critical=false
+
+    while [ $# -gt 0 ]; do
+        case "$1" in
+            --ttl)
+                [ $# -ge 2 ] || usage
+                ttl="$2"
+                shift 2
+                ;;
+            --critical)
+                critical=true
+                shift
+                ;;
+            --clear)
+                [ $# -eq 2 ] || usage
+                rm -f "$(file_for "$2")"
+                exit 0
+                ;;
+            -h|--help)
+                usage
+                ;;
+            *)
+                break
+                ;;
+        esac
+    done
+
+    [ $# -ge 2 ] && [ $# -le 3 ] || usage
+    local name="$1"
+    local status="$2"
+    local message="${3:-}"
+
+    case "$status" in
+        pass|warn|fail) ;;
+        *) echo "Invalid status '$status' (want pass, warn or fail)" >&2; exit 1 ;;
+    esac
+    if ! [[ "$ttl" =~ ^[0-9]+$ ]]; then
+        echo "Invalid --ttl value '$ttl' (want whole seconds)" >&2
+        exit 1
+    fi
+
+    mkdir -p "$DROPIN_DIR"
+    local file
+    file=$(file_for "$name")
+
+    # Write atomically so the health server never reads a partial file
+    local tmp="$file.tmp.$$"
+    printf '{"name":"%s","status":"%s","message":"%s","critical":%s,"ttl_seconds":%s,"updated_at":"%s"}\n' \
+        "$(json_escape "$name")" "$status" "$(json_escape "$message")" "$critical" "$ttl" \
+        "$(date -u +%Y-%m-%dT%H:%M:%SZ)" > "$tmp"
+    mv "$tmp" "$file"
+}
+
+main "$@"
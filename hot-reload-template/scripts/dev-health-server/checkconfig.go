@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Check types supported in the checks file
+const (
+	checkHTTP = "http"
+	checkTCP  = "tcp"
+	checkExec = "exec"
+	checkFile = "file"
+)
+
+// CheckDefinition is one entry of the checks file
+type CheckDefinition struct {
+	Name     string
+	Type     string
+	Target   string // URL, address, command or path depending on Type
+	Timeout  time.Duration
+	Interval time.Duration
+	Critical bool
+}
+
+// targetKeys maps each check type to the key holding its target
+var targetKeys = map[string]string{
+	checkHTTP: "url",
+	checkTCP:  "address",
+	checkExec: "command",
+	checkFile: "path",
+
+	checkPostgres: "url",
+	checkMySQL:    "url",
+	checkMongoDB:  "url",
+}
+
+// parseChecksFile parses the checks file. Only the YAML subset the file needs
+// is supported (a top-level "checks:" list of flat key/value maps) so the
+// server keeps building from the standard library alone:
+//
+//	checks:
+//	  - name: redis
+//	    type: tcp
+//	    address: 127.0.0.1:6379
+//	    timeout: 1s
+//	    interval: 15s
+//	    critical: false
+func parseChecksFile(r io.Reader) ([]CheckDefinition, error) {
+	var entries []map[string]string
+	var lines []int
+	inChecks := false
+	itemIndent := -1
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		raw := stripComment(scanner.Text())
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		line := strings.TrimSpace(raw)
+
+		if indent == 0 {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+			}
+			inChecks = strings.TrimSpace(key) == "checks"
+			if inChecks && strings.TrimSpace(value) != "" {
+				return nil, fmt.Errorf("line %d: \"checks\" must be a list", lineNo)
+			}
+			continue
+		}
+		if !inChecks {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "-"); ok {
+			if itemIndent == -1 {
+				itemIndent = indent
+			}
+			if indent != itemIndent {
+				return nil, fmt.Errorf("line %d: inconsistent list indentation", lineNo)
+			}
+			entries = append(entries, make(map[string]string))
+			lines = append(lines, lineNo)
+			line = strings.TrimSpace(rest)
+			if line == "" {
+				continue
+			}
+		} else if len(entries) == 0 || indent <= itemIndent {
+			return nil, fmt.Errorf("line %d: expected a list item starting with \"-\"", lineNo)
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		entries[len(entries)-1][strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	defs := make([]CheckDefinition, 0, len(entries))
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		def, err := checkFromEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("check on line %d: %w", lines[i], err)
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("check on line %d: duplicate name %q", lines[i], def.Name)
+		}
+		seen[def.Name] = true
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// checkFromEntry validates a parsed entry and applies defaults
+func checkFromEntry(entry map[string]string) (CheckDefinition, error) {
+	def := CheckDefinition{
+		Name:     entry["name"],
+		Type:     entry["type"],
+		Timeout:  2 * time.Second,
+		Interval: 10 * time.Second,
+		Critical: true,
+	}
+	if def.Name == "" {
+		return def, fmt.Errorf("missing name")
+	}
+
+	targetKey, ok := targetKeys[def.Type]
+	if !ok {
+		return def, fmt.Errorf("%s: unknown type %q (want http, tcp, exec, file, postgres, mysql or mongodb)", def.Name, def.Type)
+	}
+	def.Target = entry[targetKey]
+	if def.Target == "" {
+		return def, fmt.Errorf("%s: %s check requires %q", def.Name, def.Type, targetKey)
+	}
+
+	var err error
+	if value, ok := entry["timeout"]; ok {
+		if def.Timeout, err = parseDuration(value); err != nil {
+			return def, fmt.Errorf("%s: timeout: %w", def.Name, err)
+		}
+	}
+	if value, ok := entry["interval"]; ok {
+		if def.Interval, err = parseDuration(value); err != nil {
+			return def, fmt.Errorf("%s: interval: %w", def.Name, err)
+		}
+	}
+	if value, ok := entry["critical"]; ok {
+		if def.Critical, err = strconv.ParseBool(value); err != nil {
+			return def, fmt.Errorf("%s: critical must be true or false", def.Name)
+		}
+	}
+	return def, nil
+}
+
+// parseDuration accepts Go durations ("500ms", "10s") or bare seconds ("10")
+func parseDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if d, err = time.ParseDuration(value); err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %q", value)
+	}
+	return d, nil
+}
+
+// stripComment removes a trailing "# comment" that is not inside quotes
+func stripComment(line string) string {
+	var quote rune
+	for i, ch := range line {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote removes matching single or double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
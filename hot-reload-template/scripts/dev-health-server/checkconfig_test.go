@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseChecksFile(t *testing.T) {
+	src := `# Health checks for the dev container
+version: 1
+checks:
+  - name: redis
+    type: tcp
+    address: 127.0.0.1:6379   # local redis
+    timeout: 1s
+    interval: 15
+    critical: false
+  -
+    name: api
+    type: http
+    url: "http://localhost:8080/health#ready"
+  - name: db
+    type: postgres
+    url: 'postgres://app@localhost/app'
+other:
+  - ignored: true
+`
+	defs, err := parseChecksFile(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("parseChecksFile: %v", err)
+	}
+	want := []CheckDefinition{
+		{Name: "redis", Type: checkTCP, Target: "127.0.0.1:6379", Timeout: time.Second, Interval: 15 * time.Second, Critical: false},
+		{Name: "api", Type: checkHTTP, Target: "http://localhost:8080/health#ready", Timeout: 2 * time.Second, Interval: 10 * time.Second, Critical: true},
+		{Name: "db", Type: checkPostgres, Target: "postgres://app@localhost/app", Timeout: 2 * time.Second, Interval: 10 * time.Second, Critical: true},
+	}
+	if len(defs) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(defs), len(want), defs)
+	}
+	for i := range want {
+		if defs[i] != want[i] {
+			t.Errorf("check %d = %+v, want %+v", i, defs[i], want[i])
+		}
+	}
+}
+
+func TestParseChecksFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"top level without colon", "checks\n", "line 1: expected \"key: value\""},
+		{"checks not a list", "checks: [a]\n", "line 1: \"checks\" must be a list"},
+		{"key before item", "checks:\n  name: a\n", "line 2: expected a list item"},
+		{"item without colon", "checks:\n  - name a\n", "line 2: expected \"key: value\""},
+		{"inconsistent indentation", "checks:\n  - name: a\n    type: file\n    path: /x\n    - name: b\n", "line 5: inconsistent list indentation"},
+		{"missing name", "checks:\n  - type: tcp\n    address: :1\n", "check on line 2: missing name"},
+		{"unknown type", "checks:\n  - name: a\n    type: ping\n", "unknown type \"ping\""},
+		{"missing target", "checks:\n  - name: a\n    type: exec\n", "exec check requires \"command\""},
+		{"bad timeout", "checks:\n  - name: a\n    type: file\n    path: /x\n    timeout: soon\n", "timeout: invalid duration \"soon\""},
+		{"negative interval", "checks:\n  - name: a\n    type: file\n    path: /x\n    interval: -5s\n", "interval: duration must be positive"},
+		{"zero timeout", "checks:\n  - name: a\n    type: file\n    path: /x\n    timeout: 0\n", "timeout: duration must be positive"},
+		{"bad critical", "checks:\n  - name: a\n    type: file\n    path: /x\n    critical: maybe\n", "critical must be true or false"},
+		{"duplicate name", "checks:\n  - name: a\n    type: file\n    path: /x\n  - name: a\n    type: file\n    path: /y\n", "check on line 5: duplicate name \"a\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseChecksFile(strings.NewReader(tt.in))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseChecksFile error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a: b # note", "a: b "},
+		{"# whole line", ""},
+		{`url: "http://x/#frag" # note`, `url: "http://x/#frag" `},
+		{"url: http://x/#frag", "url: http://x/#frag"},
+		{`cmd: 'echo "#"'`, `cmd: 'echo "#"'`},
+		{`unterminated: "abc # not a comment`, `unterminated: "abc # not a comment`},
+	}
+	for _, tt := range tests {
+		if got := stripComment(tt.in); got != tt.want {
+			t.Errorf("stripComment(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
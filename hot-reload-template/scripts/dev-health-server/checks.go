@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Check result states
+const (
+	checkPass    = "pass"
+	checkWarn    = "warn"
+	checkFail    = "fail"
+	checkPending = "pending"
+)
+
+// configReloadInterval is how often the checks file is polled for changes
+const configReloadInterval = 5 * time.Second
+
+// CheckResult is the latest outcome of one named check
+type CheckResult struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Message   string `json:"message,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	CheckedAt string `json:"checked_at,omitempty"`
+}
+
+// checkRunner runs the checks from the checks file in the background
+type checkRunner struct {
+	mu      sync.RWMutex
+	results map[string]CheckResult
+	order   []string
+	cancel  context.CancelFunc
+
+	// Last loaded checks file, used to detect changes
+	path    string
+	modTime time.Time
+}
+
+// checks is the process-wide check runner, started in main
+var checks = &checkRunner{results: make(map[string]CheckResult)}
+
+// checksFilePath returns DEV_HEALTH_CHECKS_FILE or .dev-health.yaml in the workspace
+func checksFilePath() string {
+	if path := os.Getenv("DEV_HEALTH_CHECKS_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(cfg.WorkspacePath, ".dev-health.yaml")
+}
+
+// Watch loads the checks file and reloads it whenever it changes,
+// so checks committed to the synced repo take effect without a restart
+func (c *checkRunner) Watch(ctx context.Context) {
+	ticker := time.NewTicker(configReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		c.reloadIfChanged()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadIfChanged restarts the checks when the file appears, changes or disappears
+func (c *checkRunner) reloadIfChanged() {
+	path := checksFilePath()
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	if path == c.path && modTime.Equal(c.modTime) {
+		return
+	}
+	c.path, c.modTime = path, modTime
+
+	if modTime.IsZero() {
+		log.Printf("No checks file at %s", path)
+		c.start(withBuiltinChecks(nil), "")
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		c.start(withBuiltinChecks(nil), err.Error())
+		return
+	}
+	defs, err := parseChecksFile(file)
+	file.Close()
+	if err != nil {
+		log.Printf("Error loading checks file %s: %v", path, err)
+		c.start(withBuiltinChecks(nil), err.Error())
+		return
+	}
+	log.Printf("Loaded %d checks from %s", len(defs), path)
+	c.start(withBuiltinChecks(defs), "")
+}
+
+// withBuiltinChecks appends the checks derived from the environment to the
+// checks file entries; a file entry with the same name takes precedence
+func withBuiltinChecks(defs []CheckDefinition) []CheckDefinition {
+	names := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		names[def.Name] = true
+	}
+	for _, def := range databaseChecks() {
+		if !names[def.Name] {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// start replaces the running checks. A config error is reported as a failing
+// non-critical check so a typo is visible without taking the container down.
+func (c *checkRunner) start(defs []CheckDefinition, configErr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.results = make(map[string]CheckResult)
+	c.order = nil
+
+	if configErr != "" {
+		c.order = append(c.order, "checks-file")
+		c.results["checks-file"] = CheckResult{
+			Name:      "checks-file",
+			Type:      "config",
+			Status:    checkFail,
+			Message:   configErr,
+			CheckedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+
+	for _, def := range defs {
+		c.order = append(c.order, def.Name)
+		c.results[def.Name] = CheckResult{Name: def.Name, Type: def.Type, Status: checkPending, Critical: def.Critical}
+		go c.run(ctx, def)
+	}
+}
+
+// run executes one check on its interval until ctx is cancelled
+func (c *checkRunner) run(ctx context.Context, def CheckDefinition) {
+	ticker := time.NewTicker(def.Interval)
+	defer ticker.Stop()
+
+	for {
+		result := runCheck(ctx, def)
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.mu.Lock()
+		c.results[def.Name] = result
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Results returns the latest result of every check in file order
+func (c *checkRunner) Results() []CheckResult {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make([]CheckResult, 0, len(c.order))
+	for _, name := range c.order {
+		results = append(results, c.results[name])
+	}
+	return results
+}
+
+// runCheck performs a single check with its timeout
+func runCheck(ctx context.Context, def CheckDefinition) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, def.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	var detail string
+	var err error
+	switch def.Type {
+	case checkHTTP:
+		err = checkHTTPTarget(ctx, def.Target)
+	case checkTCP:
+		err = checkTCPTarget(ctx, def.Target)
+	case checkExec:
+		err = checkExecTarget(ctx, def.Target)
+	case checkFile:
+		_, err = os.Stat(def.Target)
+	case checkPostgres:
+		detail, err = checkPostgresURL(ctx, def.Target)
+	case checkMySQL:
+		detail, err = checkMySQLURL(ctx, def.Target)
+	case checkMongoDB:
+		detail, err = checkMongoDBURL(ctx, def.Target)
+	}
+
+	result := CheckResult{
+		Name:      def.Name,
+		Type:      def.Type,
+		Status:    checkPass,
+		Critical:  def.Critical,
+		Message:   detail,
+		LatencyMS: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		result.Status = checkFail
+		result.Message = err.Error()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Message = fmt.Sprintf("timed out after %s", def.Timeout)
+		}
+	}
+	return result
+}
+
+// checkHTTPTarget passes on any 2xx or 3xx response
+func checkHTTPTarget(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Errorf("returned %s", resp.Status)
+	}
+	return nil
+}
+
+// checkTCPTarget passes when the address accepts a connection
+func checkTCPTarget(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkExecTarget passes when the command exits 0; it runs in the workspace
+func checkExecTarget(ctx context.Context, command string) error {
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	cmd.Dir = cfg.WorkspacePath
+	// Pipelines leave grandchildren holding the output pipe open, so on
+	// timeout kill the whole process group and stop waiting for the pipe
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	// Surface the last line of output, which is usually the error
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		if len(last) > 200 {
+			last = last[:200]
+		}
+		return fmt.Errorf("%v: %s", err, last)
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckExecTargetTimeoutKillsPipeline(t *testing.T) {
+	cfg.WorkspacePath = t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// sleep is a grandchild holding the output pipe; killing only bash
+	// would leave CombinedOutput waiting for it
+	start := time.Now()
+	err := checkExecTarget(ctx, "sleep 10 | cat")
+	if err == nil {
+		t.Fatal("want an error from the timed-out command")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("checkExecTarget returned after %v, want shortly after the timeout", elapsed)
+	}
+}
+
+func TestCheckExecTarget(t *testing.T) {
+	cfg.WorkspacePath = t.TempDir()
+	if err := checkExecTarget(context.Background(), "true"); err != nil {
+		t.Errorf("true: %v", err)
+	}
+	err := checkExecTarget(context.Background(), "echo first; echo 'connection refused' >&2; exit 3")
+	if err == nil || err.Error() != "exit status 3: connection refused" {
+		t.Errorf("want the exit status and last output line, got %v", err)
+	}
+}
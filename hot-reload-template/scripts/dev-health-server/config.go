@@ -0,0 +1,101 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds the settings read from environment variables at startup
+type Config struct {
+	Port          int
+	AppPort       int
+	AppHealthPath string
+	ReadyTimeout  time.Duration
+
+	// Repository sync settings shared with github-sync.sh
+	RepoURL         string
+	RepoFolder      string
+	WorkspacePath   string
+	SyncInterval    time.Duration
+	SyncStaleFactor int
+
+	// FailOnPreDeploy marks the container unhealthy while PRE_DEPLOY is failing
+	FailOnPreDeploy bool
+
+	// FailUnhealthy makes /dev_health return 503 instead of 200 when unhealthy
+	FailUnhealthy bool
+
+	// Disk and memory usage thresholds, in percent
+	DiskWarnPercent   int
+	DiskFailPercent   int
+	MemoryWarnPercent int
+	MemoryFailPercent int
+
+	// Health history sampling interval and event buffer size
+	HistoryInterval time.Duration
+	HistorySize     int
+
+	// Webhook notifications; disabled when WebhookURL is empty
+	WebhookURL     string
+	WebhookSecret  string
+	WebhookFormat  string
+	WebhookRetries int
+}
+
+// cfg is the active configuration, populated once in main
+var cfg Config
+
+// loadConfig reads the server configuration from the environment
+func loadConfig() Config {
+	return Config{
+		Port:          getEnvInt("DEV_HEALTH_PORT", 9090),
+		AppPort:       getEnvInt("DEV_HEALTH_APP_PORT", 8080),
+		AppHealthPath: getEnvOrDefault("DEV_HEALTH_APP_PATH", "/health"),
+		ReadyTimeout:  time.Duration(getEnvInt("DEV_HEALTH_READY_TIMEOUT", 2)) * time.Second,
+
+		RepoURL:         os.Getenv("GITHUB_REPO_URL"),
+		RepoFolder:      os.Getenv("GITHUB_REPO_FOLDER"),
+		WorkspacePath:   getEnvOrDefault("WORKSPACE_PATH", "/workspaces/app"),
+		SyncInterval:    time.Duration(getEnvInt("GITHUB_SYNC_INTERVAL", 15)) * time.Second,
+		SyncStaleFactor: getEnvInt("DEV_HEALTH_SYNC_STALE_FACTOR", 4),
+
+		FailOnPreDeploy: getEnvOrDefault("DEV_HEALTH_FAIL_ON_PRE_DEPLOY", "false") == "true",
+		FailUnhealthy:   getEnvOrDefault("DEV_HEALTH_FAIL_UNHEALTHY", "false") == "true",
+
+		DiskWarnPercent:   getEnvInt("DEV_HEALTH_DISK_WARN_PERCENT", 85),
+		DiskFailPercent:   getEnvInt("DEV_HEALTH_DISK_FAIL_PERCENT", 95),
+		MemoryWarnPercent: getEnvInt("DEV_HEALTH_MEMORY_WARN_PERCENT", 85),
+		MemoryFailPercent: getEnvInt("DEV_HEALTH_MEMORY_FAIL_PERCENT", 95),
+
+		HistoryInterval: time.Duration(getEnvInt("DEV_HEALTH_HISTORY_INTERVAL", 10)) * time.Second,
+		HistorySize:     getEnvInt("DEV_HEALTH_HISTORY_SIZE", 1000),
+
+		WebhookURL:     os.Getenv("DEV_HEALTH_WEBHOOK_URL"),
+		WebhookSecret:  os.Getenv("DEV_HEALTH_WEBHOOK_SECRET"),
+		WebhookFormat:  getEnvOrDefault("DEV_HEALTH_WEBHOOK_FORMAT", "json"),
+		WebhookRetries: getEnvInt("DEV_HEALTH_WEBHOOK_RETRIES", 3),
+	}
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt parses an integer environment variable, falling back to the default on error
+func getEnvInt(key string, defaultValue int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Printf("Warning: Invalid %s value '%s', using default %d", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Database check types, also usable in the checks file with a "url" key
+const (
+	checkPostgres = "postgres"
+	checkMySQL    = "mysql"
+	checkMongoDB  = "mongodb"
+)
+
+// databaseURLVars are env vars checked in addition to *_DATABASE_URL and *_DB_URL
+var databaseURLVars = map[string]bool{
+	"DATABASE_URL":   true,
+	"POSTGRES_URL":   true,
+	"POSTGRESQL_URL": true,
+	"MYSQL_URL":      true,
+	"MONGODB_URI":    true,
+	"MONGODB_URL":    true,
+	"MONGO_URL":      true,
+}
+
+// databaseCheckType maps a connection URL scheme to its check type
+func databaseCheckType(scheme string) string {
+	switch scheme {
+	case "postgres", "postgresql":
+		return checkPostgres
+	case "mysql":
+		return checkMySQL
+	case "mongodb", "mongodb+srv":
+		return checkMongoDB
+	}
+	return ""
+}
+
+// databaseChecks builds a check for every DATABASE_URL-style env var with a
+// recognised scheme. Set DEV_HEALTH_DB_CHECKS=false to disable them.
+func databaseChecks() []CheckDefinition {
+	if getEnvOrDefault("DEV_HEALTH_DB_CHECKS", "true") != "true" {
+		return nil
+	}
+	critical := getEnvOrDefault("DEV_HEALTH_DB_CRITICAL", "false") == "true"
+
+	var defs []CheckDefinition
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !databaseURLVars[key] && !strings.HasSuffix(key, "_DATABASE_URL") && !strings.HasSuffix(key, "_DB_URL") {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			continue
+		}
+		checkType := databaseCheckType(u.Scheme)
+		if checkType == "" {
+			continue
+		}
+		defs = append(defs, CheckDefinition{
+			Name:     key,
+			Type:     checkType,
+			Target:   value,
+			Timeout:  5 * time.Second,
+			Interval: 30 * time.Second,
+			Critical: critical,
+		})
+	}
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// dialDatabase opens a TCP connection bounded by the context deadline
+func dialDatabase(ctx context.Context, address string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	return conn, nil
+}
+
+// tlsClient upgrades a connection for a reachability probe. Managed databases
+// use their own CA, so the certificate is not verified; no credentials are sent.
+func tlsClient(ctx context.Context, conn net.Conn, host string) (net.Conn, error) {
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// hostPort returns the first host of a connection URL with a default port
+func hostPort(u *url.URL, defaultPort string) (host, address string) {
+	// Replica set URLs list several hosts; the first one is enough to probe
+	hosts, _, _ := strings.Cut(u.Host, ",")
+	host, port, err := net.SplitHostPort(hosts)
+	if err != nil {
+		host, port = hosts, defaultPort
+	}
+	return host, net.JoinHostPort(host, port)
+}
+
+// checkPostgresURL negotiates SSL and sends a startup message, stopping at
+// the server's first authentication request. pg_hba.conf rejections (the
+// usual trusted-source failure) come back as an error before authentication.
+func checkPostgresURL(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("invalid connection URL")
+	}
+	host, address := hostPort(u, "5432")
+
+	conn, err := dialDatabase(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return probePostgres(ctx, conn, u, host, address)
+}
+
+// probePostgres runs the SSL negotiation and startup exchange on conn
+func probePostgres(ctx context.Context, conn net.Conn, u *url.URL, host, address string) (string, error) {
+	sslMode := u.Query().Get("sslmode")
+	tlsUsed := false
+	if sslMode != "disable" {
+		// SSLRequest: length 8, request code 80877103
+		request := make([]byte, 8)
+		binary.BigEndian.PutUint32(request[0:4], 8)
+		binary.BigEndian.PutUint32(request[4:8], 80877103)
+		if _, err := conn.Write(request); err != nil {
+			return "", err
+		}
+		answer := make([]byte, 1)
+		if _, err := io.ReadFull(conn, answer); err != nil {
+			return "", fmt.Errorf("SSL negotiation: %w", err)
+		}
+		switch answer[0] {
+		case 'S':
+			tlsConn, err := tlsClient(ctx, conn, host)
+			if err != nil {
+				return "", err
+			}
+			defer tlsConn.Close()
+			conn = tlsConn
+			tlsUsed = true
+		case 'N':
+			if sslMode == "require" || sslMode == "verify-ca" || sslMode == "verify-full" {
+				return "", fmt.Errorf("server at %s does not support SSL (sslmode=%s)", address, sslMode)
+			}
+		default:
+			return "", fmt.Errorf("unexpected SSL negotiation response %q", answer[0])
+		}
+	}
+
+	user := u.User.Username()
+	if user == "" {
+		user = "postgres"
+	}
+	database := strings.TrimPrefix(u.Path, "/")
+	if database == "" {
+		database = user
+	}
+
+	// StartupMessage: length, protocol 3.0, then name/value pairs
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, uint32(196608))
+	for _, s := range []string{"user", user, "database", database, ""} {
+		body.WriteString(s)
+		body.WriteByte(0)
+	}
+	startup := make([]byte, 4, 4+body.Len())
+	binary.BigEndian.PutUint32(startup, uint32(4+body.Len()))
+	if _, err := conn.Write(append(startup, body.Bytes()...)); err != nil {
+		return "", err
+	}
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("startup: %w", err)
+	}
+	length := int(binary.BigEndian.Uint32(header[1:5])) - 4
+	if length < 0 || length > 64*1024 {
+		return "", fmt.Errorf("startup: invalid message length %d", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return "", fmt.Errorf("startup: %w", err)
+	}
+
+	switch header[0] {
+	case 'R':
+		detail := "PostgreSQL accepted startup at " + address
+		if tlsUsed {
+			detail += " (TLS)"
+		}
+		return detail, nil
+	case 'E':
+		return "", fmt.Errorf("PostgreSQL rejected startup: %s", postgresErrorMessage(payload))
+	default:
+		return "", fmt.Errorf("unexpected startup response %q", header[0])
+	}
+}
+
+// postgresErrorMessage extracts the "M" field from an ErrorResponse body
+func postgresErrorMessage(payload []byte) string {
+	for len(payload) > 1 {
+		field := payload[0]
+		end := bytes.IndexByte(payload[1:], 0)
+		if end < 0 {
+			break
+		}
+		if field == 'M' {
+			return string(payload[1 : 1+end])
+		}
+		payload = payload[2+end:]
+	}
+	return "unknown error"
+}
+
+// checkMySQLURL reads the server greeting, which MySQL sends before any
+// authentication. Host-not-allowed errors arrive in place of the greeting.
+func checkMySQLURL(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("invalid connection URL")
+	}
+	_, address := hostPort(u, "3306")
+
+	conn, err := dialDatabase(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return readMySQLGreeting(conn, address)
+}
+
+// readMySQLGreeting reads and decodes the first packet the server sends
+func readMySQLGreeting(conn net.Conn, address string) (string, error) {
+	// Packet header: 3-byte little-endian length, 1-byte sequence id
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("greeting: %w", err)
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if length == 0 || length > 64*1024 {
+		return "", fmt.Errorf("greeting: invalid packet length %d", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return "", fmt.Errorf("greeting: %w", err)
+	}
+
+	switch payload[0] {
+	case 0x0a:
+		version, _, _ := bytes.Cut(payload[1:], []byte{0})
+		return fmt.Sprintf("MySQL %s greeting from %s", version, address), nil
+	case 0xff:
+		// Error packet: 2-byte code, then the message
+		if len(payload) < 3 {
+			return "", errors.New("MySQL refused connection")
+		}
+		code := binary.LittleEndian.Uint16(payload[1:3])
+		return "", fmt.Errorf("MySQL error %d: %s", code, payload[3:])
+	default:
+		return "", fmt.Errorf("unexpected greeting protocol %d", payload[0])
+	}
+}
+
+// checkMongoDBURL sends an unauthenticated hello command as an OP_MSG
+func checkMongoDBURL(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("invalid connection URL")
+	}
+	query := u.Query()
+	useTLS := query.Get("tls") == "true" || query.Get("ssl") == "true"
+
+	host, address := hostPort(u, "27017")
+	if u.Scheme == "mongodb+srv" {
+		// SRV records point at the replica set members; TLS is on by default
+		_, records, err := net.DefaultResolver.LookupSRV(ctx, "mongodb", "tcp", host)
+		if err != nil || len(records) == 0 {
+			return "", fmt.Errorf("SRV lookup for %s failed: %v", host, err)
+		}
+		host = strings.TrimSuffix(records[0].Target, ".")
+		address = net.JoinHostPort(host, fmt.Sprint(records[0].Port))
+		useTLS = query.Get("tls") != "false" && query.Get("ssl") != "false"
+	}
+
+	conn, err := dialDatabase(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if useTLS {
+		if conn, err = tlsClient(ctx, conn, host); err != nil {
+			return "", err
+		}
+		defer conn.Close()
+	}
+	return probeMongoDB(conn, address)
+}
+
+// probeMongoDB sends hello on conn and decodes the reply
+func probeMongoDB(conn net.Conn, address string) (string, error) {
+	if _, err := conn.Write(mongoHelloMessage()); err != nil {
+		return "", err
+	}
+
+	// Reply header: messageLength, requestID, responseTo, opCode
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("hello: %w", err)
+	}
+	length := int(binary.LittleEndian.Uint32(header[0:4])) - 16
+	if length < 6 || length > 1024*1024 {
+		return "", fmt.Errorf("hello: invalid reply length %d", length)
+	}
+	if opCode := binary.LittleEndian.Uint32(header[12:16]); opCode != 2013 {
+		return "", fmt.Errorf("hello: unexpected reply opcode %d", opCode)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return "", fmt.Errorf("hello: %w", err)
+	}
+
+	// Skip flagBits (4 bytes) and the section kind byte
+	fields := bsonFields(body[5:])
+	if fields["ok"] != 1.0 {
+		if msg, ok := fields["errmsg"].(string); ok {
+			return "", fmt.Errorf("MongoDB hello failed: %s", msg)
+		}
+		return "", errors.New("MongoDB hello failed")
+	}
+	role := "secondary"
+	if primary, _ := fields["isWritablePrimary"].(bool); primary {
+		role = "primary"
+	}
+	return fmt.Sprintf("MongoDB hello ok from %s (%s)", address, role), nil
+}
+
+// mongoHelloMessage encodes {hello: 1, $db: "admin"} as an OP_MSG
+func mongoHelloMessage() []byte {
+	var doc bytes.Buffer
+	doc.WriteByte(0x10) // int32
+	doc.WriteString("hello\x00")
+	binary.Write(&doc, binary.LittleEndian, int32(1))
+	doc.WriteByte(0x02) // string
+	doc.WriteString("$db\x00")
+	binary.Write(&doc, binary.LittleEndian, int32(len("admin")+1))
+	doc.WriteString("admin\x00")
+	doc.WriteByte(0x00)
+
+	var bson bytes.Buffer
+	binary.Write(&bson, binary.LittleEndian, int32(4+doc.Len()))
+	bson.Write(doc.Bytes())
+
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.LittleEndian, int32(16+4+1+bson.Len())) // messageLength
+	binary.Write(&msg, binary.LittleEndian, int32(1))                 // requestID
+	binary.Write(&msg, binary.LittleEndian, int32(0))                 // responseTo
+	binary.Write(&msg, binary.LittleEndian, int32(2013))              // OP_MSG
+	binary.Write(&msg, binary.LittleEndian, uint32(0))                // flagBits
+	msg.WriteByte(0)                                                  // section kind: body
+	msg.Write(bson.Bytes())
+	return msg.Bytes()
+}
+
+// bsonFields decodes the top-level scalar fields of a BSON document that the
+// hello check needs. Numbers become float64; unknown types stop the walk.
+func bsonFields(doc []byte) map[string]any {
+	fields := make(map[string]any)
+	if len(doc) < 5 {
+		return fields
+	}
+	pos := 4
+	for pos < len(doc) && doc[pos] != 0 {
+		kind := doc[pos]
+		end := bytes.IndexByte(doc[pos+1:], 0)
+		if end < 0 {
+			return fields
+		}
+		name := string(doc[pos+1 : pos+1+end])
+		pos += 2 + end
+
+		size := 0
+		switch kind {
+		case 0x01: // double
+			size = 8
+			if pos+size <= len(doc) {
+				fields[name] = math.Float64frombits(binary.LittleEndian.Uint64(doc[pos:]))
+			}
+		case 0x02: // string
+			if pos+4 > len(doc) {
+				return fields
+			}
+			size = 4 + int(binary.LittleEndian.Uint32(doc[pos:]))
+			if pos+size <= len(doc) && size > 4 {
+				fields[name] = string(doc[pos+4 : pos+size-1])
+			}
+		case 0x03, 0x04: // document, array
+			if pos+4 > len(doc) {
+				return fields
+			}
+			size = int(binary.LittleEndian.Uint32(doc[pos:]))
+		case 0x05: // binary
+			if pos+4 > len(doc) {
+				return fields
+			}
+			size = 5 + int(binary.LittleEndian.Uint32(doc[pos:]))
+		case 0x07: // ObjectId
+			size = 12
+		case 0x08: // bool
+			size = 1
+			if pos < len(doc) {
+				fields[name] = doc[pos] == 1
+			}
+		case 0x0A: // null
+		case 0x10: // int32
+			size = 4
+			if pos+size <= len(doc) {
+				fields[name] = float64(int32(binary.LittleEndian.Uint32(doc[pos:])))
+			}
+		case 0x09, 0x11, 0x12: // datetime, timestamp, int64
+			size = 8
+			if kind == 0x12 && pos+size <= len(doc) {
+				fields[name] = float64(int64(binary.LittleEndian.Uint64(doc[pos:])))
+			}
+		case 0x13: // decimal128
+			size = 16
+		default:
+			return fields
+		}
+		pos += size
+	}
+	return fields
+}
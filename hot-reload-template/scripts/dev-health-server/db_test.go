@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipeServer returns the client end of a pipe whose server end runs serve,
+// closing it afterwards so a short reply reads as EOF
+func pipeServer(t *testing.T, serve func(conn net.Conn)) net.Conn {
+	t.Helper()
+	client, server := net.Pipe()
+	client.SetDeadline(time.Now().Add(2 * time.Second))
+	server.SetDeadline(time.Now().Add(2 * time.Second))
+	go func() {
+		defer server.Close()
+		serve(server)
+	}()
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// readPostgresStartup consumes the length-prefixed startup message
+func readPostgresStartup(conn net.Conn) {
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(conn, size); err != nil {
+		return
+	}
+	io.CopyN(io.Discard, conn, int64(binary.BigEndian.Uint32(size))-4)
+}
+
+// postgresMessage frames a backend message
+func postgresMessage(kind byte, payload []byte) []byte {
+	msg := []byte{kind, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(msg[1:], uint32(4+len(payload)))
+	return append(msg, payload...)
+}
+
+func TestProbePostgres(t *testing.T) {
+	authOK := postgresMessage('R', []byte{0, 0, 0, 3})
+	rejected := postgresMessage('E', []byte("SFATAL\x00C28000\x00Mno pg_hba.conf entry for host\x00\x00"))
+	tests := []struct {
+		name    string
+		url     string
+		ssl     []byte // answer to SSLRequest, nil when sslmode=disable
+		reply   []byte
+		want    string
+		wantErr string
+	}{
+		{name: "auth request without ssl", url: "postgres://app@db/app?sslmode=disable", reply: authOK, want: "PostgreSQL accepted startup at db:5432"},
+		{name: "ssl declined", url: "postgres://app@db/app", ssl: []byte("N"), reply: authOK, want: "PostgreSQL accepted startup at db:5432"},
+		{name: "ssl required but declined", url: "postgres://app@db/app?sslmode=require", ssl: []byte("N"), wantErr: "does not support SSL"},
+		{name: "bad ssl answer", url: "postgres://db", ssl: []byte("X"), wantErr: `unexpected SSL negotiation response 'X'`},
+		{name: "no ssl answer", url: "postgres://db", ssl: []byte{}, wantErr: "SSL negotiation: EOF"},
+		{name: "pg_hba rejection", url: "postgres://db?sslmode=disable", reply: rejected, wantErr: "PostgreSQL rejected startup: no pg_hba.conf entry for host"},
+		{name: "error without message", url: "postgres://db?sslmode=disable", reply: postgresMessage('E', []byte("SFATAL")), wantErr: "rejected startup: unknown error"},
+		{name: "unexpected message", url: "postgres://db?sslmode=disable", reply: postgresMessage('Z', nil), wantErr: "unexpected startup response 'Z'"},
+		{name: "truncated header", url: "postgres://db?sslmode=disable", reply: []byte{'R', 0, 0}, wantErr: "startup: unexpected EOF"},
+		{name: "truncated payload", url: "postgres://db?sslmode=disable", reply: authOK[:7], wantErr: "startup: unexpected EOF"},
+		{name: "length below header", url: "postgres://db?sslmode=disable", reply: []byte{'R', 0, 0, 0, 1}, wantErr: "invalid message length -3"},
+		{name: "oversized length", url: "postgres://db?sslmode=disable", reply: []byte{'R', 0xff, 0xff, 0xff, 0xff}, wantErr: "invalid message length"},
+		{name: "no reply", url: "postgres://db?sslmode=disable", reply: []byte{}, wantErr: "startup: EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn := pipeServer(t, func(conn net.Conn) {
+				if tt.ssl != nil {
+					io.CopyN(io.Discard, conn, 8)
+					conn.Write(tt.ssl)
+					if len(tt.ssl) == 0 || tt.ssl[0] != 'N' {
+						return
+					}
+				}
+				readPostgresStartup(conn)
+				conn.Write(tt.reply)
+			})
+			got, err := probePostgres(context.Background(), conn, u, "db", "db:5432")
+			checkProbeResult(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestPostgresErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"message field", "SERROR\x00Mboom\x00\x00", "boom"},
+		{"no message field", "SERROR\x00\x00", "unknown error"},
+		{"unterminated field", "SERROR\x00Mboo", "unknown error"},
+		{"empty", "", "unknown error"},
+		{"single byte", "M", "unknown error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgresErrorMessage([]byte(tt.in)); got != tt.want {
+				t.Errorf("postgresErrorMessage(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// mysqlPacket frames a payload with its length and sequence id
+func mysqlPacket(payload []byte) []byte {
+	n := len(payload)
+	return append([]byte{byte(n), byte(n >> 8), byte(n >> 16), 0}, payload...)
+}
+
+func TestReadMySQLGreeting(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   []byte
+		want    string
+		wantErr string
+	}{
+		{name: "greeting", reply: mysqlPacket([]byte("\x0a8.0.36\x00\x01\x00\x00\x00")), want: "MySQL 8.0.36 greeting from db:3306"},
+		{name: "greeting without terminator", reply: mysqlPacket([]byte("\x0a8.0")), want: "MySQL 8.0 greeting from db:3306"},
+		{name: "host not allowed", reply: mysqlPacket([]byte("\xff\x6a\x04Host '10.0.0.1' is not allowed")), wantErr: "MySQL error 1130: Host '10.0.0.1' is not allowed"},
+		{name: "short error packet", reply: mysqlPacket([]byte{0xff, 0x6a}), wantErr: "MySQL refused connection"},
+		{name: "unknown protocol", reply: mysqlPacket([]byte{0x09}), wantErr: "unexpected greeting protocol 9"},
+		{name: "zero length", reply: []byte{0, 0, 0, 0}, wantErr: "invalid packet length 0"},
+		{name: "oversized length", reply: []byte{0xff, 0xff, 0xff, 0}, wantErr: "invalid packet length"},
+		{name: "truncated header", reply: []byte{5, 0}, wantErr: "greeting: unexpected EOF"},
+		{name: "truncated payload", reply: []byte{10, 0, 0, 0, 0x0a, '8'}, wantErr: "greeting: unexpected EOF"},
+		{name: "no reply", reply: []byte{}, wantErr: "greeting: EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := pipeServer(t, func(conn net.Conn) { conn.Write(tt.reply) })
+			got, err := readMySQLGreeting(conn, "db:3306")
+			checkProbeResult(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+// bsonDoc wraps encoded elements in a document with its length and terminator
+func bsonDoc(elements ...[]byte) []byte {
+	body := bytes.Join(elements, nil)
+	doc := binary.LittleEndian.AppendUint32(nil, uint32(4+len(body)+1))
+	return append(append(doc, body...), 0)
+}
+
+func bsonDouble(name string, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(append([]byte{0x01}, name+"\x00"...), math.Float64bits(v))
+}
+
+func bsonString(name, v string) []byte {
+	elem := binary.LittleEndian.AppendUint32(append([]byte{0x02}, name+"\x00"...), uint32(len(v)+1))
+	return append(append(elem, v...), 0)
+}
+
+func bsonBool(name string, v bool) []byte {
+	b := byte(0)
+	if v {
+		b = 1
+	}
+	return append(append([]byte{0x08}, name+"\x00"...), b)
+}
+
+// mongoReply frames a BSON document as an OP_MSG reply
+func mongoReply(opCode uint32, doc []byte) []byte {
+	msg := binary.LittleEndian.AppendUint32(nil, uint32(16+4+1+len(doc)))
+	msg = binary.LittleEndian.AppendUint32(msg, 7)
+	msg = binary.LittleEndian.AppendUint32(msg, 1)
+	msg = binary.LittleEndian.AppendUint32(msg, opCode)
+	msg = binary.LittleEndian.AppendUint32(msg, 0)
+	msg = append(msg, 0)
+	return append(msg, doc...)
+}
+
+func TestProbeMongoDB(t *testing.T) {
+	primary := mongoReply(2013, bsonDoc(bsonBool("isWritablePrimary", true), bsonDouble("ok", 1)))
+	tests := []struct {
+		name    string
+		reply   []byte
+		want    string
+		wantErr string
+	}{
+		{name: "primary", reply: primary, want: "MongoDB hello ok from db:27017 (primary)"},
+		{name: "secondary", reply: mongoReply(2013, bsonDoc(bsonBool("isWritablePrimary", false), bsonDouble("ok", 1))), want: "(secondary)"},
+		{name: "command error", reply: mongoReply(2013, bsonDoc(bsonDouble("ok", 0), bsonString("errmsg", "not authorized"))), wantErr: "MongoDB hello failed: not authorized"},
+		{name: "no ok field", reply: mongoReply(2013, bsonDoc()), wantErr: "MongoDB hello failed"},
+		{name: "wrong opcode", reply: mongoReply(1, bsonDoc(bsonDouble("ok", 1))), wantErr: "unexpected reply opcode 1"},
+		{name: "length below header", reply: []byte{16, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xdd, 7, 0, 0}, wantErr: "invalid reply length 0"},
+		{name: "oversized length", reply: []byte{0xff, 0xff, 0xff, 0x7f, 0, 0, 0, 0, 0, 0, 0, 0, 0xdd, 7, 0, 0}, wantErr: "invalid reply length"},
+		{name: "truncated header", reply: primary[:10], wantErr: "hello: unexpected EOF"},
+		{name: "truncated body", reply: primary[:len(primary)-4], wantErr: "hello: unexpected EOF"},
+		{name: "no reply", reply: []byte{}, wantErr: "hello: EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := pipeServer(t, func(conn net.Conn) {
+				io.CopyN(io.Discard, conn, int64(len(mongoHelloMessage())))
+				conn.Write(tt.reply)
+			})
+			got, err := probeMongoDB(conn, "db:27017")
+			checkProbeResult(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestBSONFields(t *testing.T) {
+	full := bsonDoc(
+		bsonBool("isWritablePrimary", true),
+		bsonString("msg", "isdbgrid"),
+		bsonDouble("ok", 1),
+		append([]byte{0x10}, "maxWireVersion\x00\x15\x00\x00\x00"...),
+		append([]byte{0x12}, "n\x00\xfe\xff\xff\xff\xff\xff\xff\xff"...),
+		append(append([]byte{0x03}, "topologyVersion\x00"...), bsonDoc(bsonDouble("counter", 0))...),
+		append([]byte{0x0A}, "nothing\x00"...),
+		bsonString("after", "nested"),
+	)
+	fields := bsonFields(full)
+	want := map[string]any{
+		"isWritablePrimary": true,
+		"msg":               "isdbgrid",
+		"ok":                1.0,
+		"maxWireVersion":    21.0,
+		"n":                 -2.0,
+		"after":             "nested",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("fields[%q] = %v, want %v", key, fields[key], value)
+		}
+	}
+	if _, ok := fields["counter"]; ok {
+		t.Error("nested document fields should not be read as top-level fields")
+	}
+
+	// Every truncation of a valid document, and garbage lengths, must not panic
+	for i := range full {
+		bsonFields(full[:i])
+	}
+	malformed := [][]byte{
+		nil,
+		{5, 0, 0, 0},
+		{0xff, 0, 0, 0, 0x02, 'a', 0, 0xff, 0xff, 0xff, 0xff},
+		{0xff, 0, 0, 0, 0x02, 'a', 0, 0, 0, 0, 0},
+		{0xff, 0, 0, 0, 0x03, 'a', 0, 0xff, 0xff, 0xff, 0x7f, 0x01},
+		{0xff, 0, 0, 0, 0x05, 'a', 0, 0xff, 0xff, 0xff, 0xff},
+		{0xff, 0, 0, 0, 0x01, 'a', 0, 1, 2},
+		{0xff, 0, 0, 0, 0x08, 'a', 0},
+		{0xff, 0, 0, 0, 0x10, 'a'},
+		{0xff, 0, 0, 0, 0x7f, 'a', 0, 1},
+	}
+	for _, doc := range malformed {
+		bsonFields(doc)
+	}
+	if fields := bsonFields([]byte{0xff, 0, 0, 0, 0x7f, 'a', 0, 0x01, 'b', 0}); len(fields) != 0 {
+		t.Errorf("unknown type should stop the walk, got %v", fields)
+	}
+}
+
+// checkProbeResult compares a probe's detail or error with the expected text
+func checkProbeResult(t *testing.T, got string, err error, want, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("error = %v, want it to contain %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, want) {
+		t.Errorf("detail = %q, want it to contain %q", got, want)
+	}
+}
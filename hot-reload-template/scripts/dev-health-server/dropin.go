@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultDropinDir is where scripts write status files with dev-health-report
+const defaultDropinDir = "/tmp/dev-health.d"
+
+// DropinReport is one status file written by a script
+type DropinReport struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Critical   bool   `json:"critical"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// dropinDir returns DEV_HEALTH_DROPIN_DIR or the default directory
+func dropinDir() string {
+	return getEnvOrDefault("DEV_HEALTH_DROPIN_DIR", defaultDropinDir)
+}
+
+// dropinChecks turns every *.json file in the drop-in directory into a check.
+// Unreadable files and reports older than their ttl_seconds become failures,
+// so a script that stopped reporting is not mistaken for a passing one.
+func dropinChecks(now time.Time) []CheckResult {
+	files, err := filepath.Glob(filepath.Join(dropinDir(), "*.json"))
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	sort.Strings(files)
+
+	results := make([]CheckResult, 0, len(files))
+	for _, file := range files {
+		results = append(results, readDropinCheck(file, now))
+	}
+	return results
+}
+
+// readDropinCheck reads one status file; its name defaults to the file name
+func readDropinCheck(file string, now time.Time) CheckResult {
+	result := CheckResult{
+		Name:   strings.TrimSuffix(filepath.Base(file), ".json"),
+		Type:   "dropin",
+		Status: checkFail,
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	var report DropinReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		result.Message = fmt.Sprintf("invalid status file %s: %v", file, err)
+		return result
+	}
+
+	if report.Name != "" {
+		result.Name = report.Name
+	}
+	result.Critical = report.Critical
+	updated := info.ModTime()
+	if t, err := time.Parse(time.RFC3339, report.UpdatedAt); err == nil {
+		updated = t
+	}
+	result.CheckedAt = updated.UTC().Format(time.RFC3339)
+
+	switch report.Status {
+	case checkPass, checkWarn, checkFail:
+	default:
+		result.Message = fmt.Sprintf("invalid status %q in %s (want pass, warn or fail)", report.Status, file)
+		return result
+	}
+
+	if report.TTLSeconds > 0 {
+		ttl := time.Duration(report.TTLSeconds) * time.Second
+		if age := now.Sub(updated); age > ttl {
+			result.Message = fmt.Sprintf("stale: last reported %s ago (ttl %s)", age.Truncate(time.Second), ttl)
+			if report.Message != "" {
+				result.Message += ": " + report.Message
+			}
+			return result
+		}
+	}
+
+	result.Status = report.Status
+	result.Message = report.Message
+	return result
+}
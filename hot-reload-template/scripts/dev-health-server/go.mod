@@ -0,0 +1,3 @@
+module dev-health-server
+
+go 1.23
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// availabilityWindows are the periods /dev_health/history reports on; the
+// sample buffer is sized to cover the longest one
+var availabilityWindows = []struct {
+	label  string
+	period time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"24h", 24 * time.Hour},
+}
+
+// History event kinds
+const (
+	eventStatus = "status"
+	eventCheck  = "check"
+	eventApp    = "app"
+)
+
+// HistoryEvent is one recorded transition of the overall status, a check or the app
+type HistoryEvent struct {
+	Time    string `json:"time"`
+	Kind    string `json:"kind"`
+	Name    string `json:"name,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Message string `json:"message,omitempty"`
+}
+
+// Availability summarises the samples taken during one window. Samples taken
+// while the container was starting are not counted.
+type Availability struct {
+	Window           string  `json:"window"`
+	Samples          int     `json:"samples"`
+	HealthyPercent   float64 `json:"healthy_percent"`
+	AppUpPercent     float64 `json:"app_up_percent"`
+	UnhealthyPeriods int     `json:"unhealthy_periods"`
+	AppDownPeriods   int     `json:"app_down_periods"`
+}
+
+// HistoryResponse is the JSON response for the /dev_health/history endpoint
+type HistoryResponse struct {
+	Timestamp       string         `json:"timestamp"`
+	IntervalSeconds float64        `json:"interval_seconds"`
+	Since           string         `json:"since"`
+	Availability    []Availability `json:"availability"`
+	Events          []HistoryEvent `json:"events"`
+}
+
+// healthSample is one evaluation kept for availability stats
+type healthSample struct {
+	at       time.Time
+	starting bool
+	healthy  bool
+	appUp    bool
+}
+
+// healthHistory evaluates health on a fixed interval and keeps bounded ring
+// buffers of samples and transitions
+type healthHistory struct {
+	mu       sync.RWMutex
+	since    time.Time
+	interval time.Duration
+
+	samples    []healthSample
+	sampleNext int
+	sampleFull bool
+
+	events    []HistoryEvent
+	eventNext int
+	eventFull bool
+
+	// Last seen states, used to detect transitions
+	lastStatus string
+	lastChecks map[string]string
+	lastApp    string
+}
+
+// history is the process-wide health history, started in main
+var history = &healthHistory{}
+
+// Watch evaluates health every interval until ctx is cancelled, feeding the
+// history and the webhook notifier
+func (h *healthHistory) Watch(ctx context.Context, interval time.Duration, maxEvents int) {
+	if interval <= 0 {
+		log.Printf("Warning: Invalid history interval %s, using default 10s", interval)
+		interval = 10 * time.Second
+	}
+	if maxEvents <= 0 {
+		log.Printf("Warning: Invalid history size %d, using default 1000", maxEvents)
+		maxEvents = 1000
+	}
+	longest := availabilityWindows[len(availabilityWindows)-1].period
+	h.mu.Lock()
+	h.since = time.Now()
+	h.interval = interval
+	h.samples = make([]healthSample, int(longest/interval)+1)
+	h.events = make([]HistoryEvent, maxEvents)
+	h.lastChecks = make(map[string]string)
+	h.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		report := buildHealthReport(now)
+		probe := probeUpstream(cfg.ReadyTimeout)
+		recordUpstream(probe)
+		h.record(now, report, probe)
+		notifier.Observe(report)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record stores a sample and any transitions since the previous evaluation
+func (h *healthHistory) record(now time.Time, report HealthResponse, probe UpstreamProbe) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	at := now.UTC().Format(time.RFC3339)
+	if report.Status != h.lastStatus {
+		h.addEvent(HistoryEvent{Time: at, Kind: eventStatus, From: h.lastStatus, To: report.Status, Message: report.Phase})
+		h.lastStatus = report.Status
+	}
+
+	for _, check := range report.Checks {
+		// Pending checks have not run yet; wait for a real result
+		if check.Status == checkPending || check.Status == h.lastChecks[check.Name] {
+			continue
+		}
+		h.addEvent(HistoryEvent{Time: at, Kind: eventCheck, Name: check.Name, From: h.lastChecks[check.Name], To: check.Status, Message: check.Message})
+		h.lastChecks[check.Name] = check.Status
+	}
+
+	appState := "up"
+	if !probe.Healthy() {
+		appState = "down"
+	}
+	if appState != h.lastApp {
+		h.addEvent(HistoryEvent{Time: at, Kind: eventApp, From: h.lastApp, To: appState, Message: probe.Error})
+		h.lastApp = appState
+	}
+
+	h.samples[h.sampleNext] = healthSample{
+		at:       now,
+		starting: report.Status == statusStarting,
+		healthy:  report.Status != statusUnhealthy,
+		appUp:    probe.Healthy(),
+	}
+	h.sampleNext = (h.sampleNext + 1) % len(h.samples)
+	if h.sampleNext == 0 {
+		h.sampleFull = true
+	}
+}
+
+// addEvent appends to the event ring buffer, overwriting the oldest entry when full
+func (h *healthHistory) addEvent(event HistoryEvent) {
+	h.events[h.eventNext] = event
+	h.eventNext = (h.eventNext + 1) % len(h.events)
+	if h.eventNext == 0 {
+		h.eventFull = true
+	}
+}
+
+// Events returns up to limit recorded transitions, newest first
+func (h *healthHistory) Events(limit int) []HistoryEvent {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := h.eventNext
+	if h.eventFull {
+		count = len(h.events)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	events := make([]HistoryEvent, 0, count)
+	for i := 1; i <= count; i++ {
+		events = append(events, h.events[(h.eventNext-i+len(h.events))%len(h.events)])
+	}
+	return events
+}
+
+// Availability computes uptime over each window. A "period" is a run of
+// consecutive failing samples, so a flapping app shows as many short periods.
+func (h *healthHistory) Availability(now time.Time) []Availability {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Oldest sample first
+	var ordered []healthSample
+	if h.sampleFull {
+		ordered = append(ordered, h.samples[h.sampleNext:]...)
+	}
+	ordered = append(ordered, h.samples[:h.sampleNext]...)
+
+	result := make([]Availability, 0, len(availabilityWindows))
+	for _, window := range availabilityWindows {
+		stats := Availability{Window: window.label}
+		var healthy, appUp int
+		wasHealthy, wasUp := true, true
+		for _, sample := range ordered {
+			if now.Sub(sample.at) > window.period || sample.starting {
+				continue
+			}
+			stats.Samples++
+			if sample.healthy {
+				healthy++
+			} else if wasHealthy {
+				stats.UnhealthyPeriods++
+			}
+			if sample.appUp {
+				appUp++
+			} else if wasUp {
+				stats.AppDownPeriods++
+			}
+			wasHealthy, wasUp = sample.healthy, sample.appUp
+		}
+		if stats.Samples > 0 {
+			stats.HealthyPercent = roundPercent(float64(healthy) / float64(stats.Samples))
+			stats.AppUpPercent = roundPercent(float64(appUp) / float64(stats.Samples))
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
+// historyHandler handles requests to the /dev_health/history endpoint
+func historyHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /dev_health/history endpoint
+	if r.URL.Path != "/dev_health/history" {
+		http.NotFound(w, r)
+		return
+	}
+
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	now := time.Now()
+	history.mu.RLock()
+	since, interval := history.since, history.interval
+	history.mu.RUnlock()
+
+	response := HistoryResponse{
+		Timestamp:       now.UTC().Format(time.RFC3339),
+		IntervalSeconds: interval.Seconds(),
+		Since:           since.UTC().Format(time.RFC3339),
+		Availability:    history.Availability(now),
+		Events:          history.Events(limit),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// jobStateDir mirrors JOB_STATE_DIR in job-manager.sh
+const jobStateDir = "/tmp/dev-jobs"
+
+// jobTypes lists the deploy jobs job-manager.sh knows how to run
+var jobTypes = []string{"PRE_DEPLOY", "POST_DEPLOY"}
+
+// JobStatus is the result file job-manager.sh writes for each job run
+type JobStatus struct {
+	JobType         string `json:"job_type"`
+	Commit          string `json:"commit,omitempty"`
+	Status          string `json:"status"`
+	ExitCode        *int   `json:"exit_code"`
+	TimedOut        bool   `json:"timed_out"`
+	StartedAt       string `json:"started_at"`
+	FinishedAt      string `json:"finished_at,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds"`
+}
+
+// Failed reports whether the last run finished unsuccessfully
+func (j JobStatus) Failed() bool {
+	return j.Status == "failed" || j.Status == "timeout"
+}
+
+// readJobStatuses loads the last recorded run of each deploy job, keyed by job type.
+// Jobs that have never run are omitted.
+func readJobStatuses() map[string]JobStatus {
+	jobs := make(map[string]JobStatus)
+	for _, jobType := range jobTypes {
+		data, err := os.ReadFile(filepath.Join(jobStateDir, jobType+".json"))
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				log.Printf("Error reading %s job state: %v", jobType, err)
+			}
+			continue
+		}
+
+		var job JobStatus
+		if err := json.Unmarshal(data, &job); err != nil {
+			log.Printf("Error parsing %s job state: %v", jobType, err)
+			continue
+		}
+		jobs[jobType] = job
+	}
+	return jobs
+}
+
+// preDeployFailedForCommit reports whether PRE_DEPLOY failed for the given commit.
+// An unknown commit counts as a match so a failure is never masked by a missing repo.
+func preDeployFailedForCommit(jobs map[string]JobStatus, commit string) bool {
+	job, ok := jobs["PRE_DEPLOY"]
+	if !ok || !job.Failed() {
+		return false
+	}
+	return commit == "" || job.Commit == "" || job.Commit == commit
+}
@@ -1,20 +1,106 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"strconv"
 	"time"
 )
 
+// Overall health states, in increasing order of severity
+const (
+	statusOK        = "ok"
+	statusDegraded  = "degraded"
+	statusUnhealthy = "unhealthy"
+)
+
+// statusStarting is reported until startup.sh reaches its final phase
+const statusStarting = "starting"
+
+// statusSeverity orders overall states so the worst one wins
+var statusSeverity = map[string]int{
+	statusOK:        0,
+	statusDegraded:  1,
+	statusUnhealthy: 2,
+}
+
 // HealthResponse represents the JSON response for the health endpoint
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Service   string `json:"service"`
-	Timestamp string `json:"timestamp"`
+	Status    string               `json:"status"`
+	Service   string               `json:"service"`
+	Timestamp string               `json:"timestamp"`
+	Phase     string               `json:"phase,omitempty"`
+	Checks    []CheckResult        `json:"checks,omitempty"`
+	Sync      *SyncStatus          `json:"sync,omitempty"`
+	Jobs      map[string]JobStatus `json:"jobs,omitempty"`
+	Resources *ResourceStatus      `json:"resources,omitempty"`
+}
+
+// degrade raises the response status to at least the given state
+func (h *HealthResponse) degrade(status string) {
+	if statusSeverity[status] > statusSeverity[h.Status] {
+		h.Status = status
+	}
+}
+
+// buildHealthReport combines checks, sync and job state into one response
+func buildHealthReport(now time.Time) HealthResponse {
+	response := HealthResponse{
+		Status:    statusOK,
+		Service:   "dev-container",
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+
+	// Checks from the checks file, the built-in process, disk and memory
+	// checks and scripts' drop-in status files: a failing critical check makes the container unhealthy, any
+	// other failure only degrades it
+	resourceStatus := readResourceStatus()
+	response.Resources = &resourceStatus
+	response.Checks = append(checks.Results(), processChecks(readRecordedProcesses())...)
+	response.Checks = append(response.Checks, resourceChecks(resourceStatus, now)...)
+	response.Checks = append(response.Checks, dropinChecks(now)...)
+	for _, check := range response.Checks {
+		switch {
+		case check.Status == checkFail && check.Critical:
+			response.degrade(statusUnhealthy)
+		case check.Status == checkFail || check.Status == checkWarn:
+			response.degrade(statusDegraded)
+		}
+	}
+
+	// Report sync freshness once a repository is configured. Stale code is
+	// "degraded", not down: the container still serves the last good commit.
+	commit := ""
+	if cfg.RepoURL != "" {
+		sync := readSyncStatus(now)
+		response.Sync = &sync
+		commit = sync.Commit
+		if !sync.Healthy() {
+			response.degrade(statusDegraded)
+		}
+	}
+
+	// Report deploy jobs; a failing PRE_DEPLOY for the deployed commit is
+	// fatal only when explicitly requested
+	if jobs := readJobStatuses(); len(jobs) > 0 {
+		response.Jobs = jobs
+		if cfg.FailOnPreDeploy && preDeployFailedForCommit(jobs, commit) {
+			response.degrade(statusUnhealthy)
+		}
+	}
+
+	// Until the app phase is reached the container is still booting: failing
+	// checks are expected, so report "starting" rather than a failure
+	if startup, ok := readStartupStatus(now); ok {
+		response.Phase = startup.Phase
+		if !startup.Complete {
+			response.Status = statusStarting
+		}
+	}
+
+	return response
 }
 
 // healthHandler handles requests to the /dev_health endpoint
@@ -25,16 +111,19 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create health response
-	response := HealthResponse{
-		Status:    "ok",
-		Service:   "dev-container",
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	response := buildHealthReport(time.Now())
+	recordProbe("dev_health", response.Status)
+
+	// /dev_health is the App Platform liveness check: a 503 gets the container
+	// restarted, so an unhealthy status only fails it when opted in
+	statusCode := http.StatusOK
+	if response.Status == statusUnhealthy && cfg.FailUnhealthy {
+		statusCode = http.StatusServiceUnavailable
 	}
 
 	// Set content type header
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(statusCode)
 
 	// Encode and send JSON response
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -43,19 +132,36 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
-	// Get port from environment variable, default to 9090
-	port := 9090
-	if portStr := os.Getenv("DEV_HEALTH_PORT"); portStr != "" {
-		if p, err := strconv.Atoi(portStr); err == nil {
-			port = p
-		} else {
-			log.Printf("Warning: Invalid DEV_HEALTH_PORT value '%s', using default %d", portStr, port)
-		}
+	// Load configuration from environment variables (port defaults to 9090)
+	cfg = loadConfig()
+	port := cfg.Port
+
+	// Run checks from the checks file in the background
+	go checks.Watch(context.Background())
+
+	// Watch the cgroup for OOM kills
+	go resources.Watch(context.Background())
+
+	// Send webhooks on transitions when a URL is configured
+	if cfg.WebhookURL != "" {
+		notifier = newWebhookNotifier(cfg)
+		log.Printf("Sending %s webhooks to %s", cfg.WebhookFormat, webhookHost(cfg.WebhookURL))
 	}
 
+	// Evaluate health on a fixed interval to keep history and availability
+	go history.Watch(context.Background(), cfg.HistoryInterval, cfg.HistorySize)
+
 	// Create HTTP server
 	mux := http.NewServeMux()
 	mux.HandleFunc("/dev_health", healthHandler)
+	mux.HandleFunc("/dev_health/history", historyHandler)
+	mux.HandleFunc("/dev_ready", readyHandler)
+	mux.HandleFunc("/dev_status", statusHandler)
+	mux.HandleFunc("/dev_processes", processesHandler)
+	mux.HandleFunc("/metrics", metricsHandler)
+	mux.HandleFunc("/livez", probeHandler("livez", livezChecks))
+	mux.HandleFunc("/readyz", probeHandler("readyz", readyzChecks))
+	mux.HandleFunc("/startupz", probeHandler("startupz", startupzChecks))
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%d", port),
@@ -68,6 +174,12 @@ func main() {
 	// Log server start
 	log.Printf("Dev health check server starting on port %d", port)
 	log.Printf("Health endpoint: http://0.0.0.0:%d/dev_health", port)
+	log.Printf("Health history endpoint: http://0.0.0.0:%d/dev_health/history", port)
+	log.Printf("Readiness endpoint: http://0.0.0.0:%d/dev_ready (probing app at :%d%s)", port, cfg.AppPort, cfg.AppHealthPath)
+	log.Printf("Startup status endpoint: http://0.0.0.0:%d/dev_status", port)
+	log.Printf("Process inventory endpoint: http://0.0.0.0:%d/dev_processes", port)
+	log.Printf("Metrics endpoint: http://0.0.0.0:%d/metrics", port)
+	log.Printf("Probe endpoints: http://0.0.0.0:%d/livez, /readyz, /startupz (?verbose for details)", port)
 
 	// Start server
 	if err := server.ListenAndServe(); err != nil {
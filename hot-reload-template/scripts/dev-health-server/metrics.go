@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syncStateFile mirrors SYNC_STATE_FILE in github-sync.sh
+const syncStateFile = "/tmp/dev-sync-state.json"
+
+// jobRunsFile mirrors JOB_RUNS_FILE in job-manager.sh
+var jobRunsFile = filepath.Join(jobStateDir, "runs.jsonl")
+
+// processStart is used to report process uptime
+var processStart = time.Now()
+
+// SyncState is the counter file github-sync.sh rewrites after every sync cycle
+type SyncState struct {
+	Attempts    int64  `json:"attempts"`
+	Failures    int64  `json:"failures"`
+	LastAttempt string `json:"last_attempt"`
+	LastSuccess string `json:"last_success"`
+	Commit      string `json:"commit"`
+	CommitTime  string `json:"commit_time"`
+}
+
+// probeKey identifies a health probe counter
+type probeKey struct {
+	endpoint string
+	status   string
+}
+
+// probeStats counts health probes served by this process
+var probeStats = struct {
+	sync.Mutex
+	counts          map[probeKey]uint64
+	upstreamLatency time.Duration
+	upstreamUp      bool
+	upstreamProbed  bool
+}{counts: make(map[probeKey]uint64)}
+
+// recordProbe counts a response served by a health endpoint
+func recordProbe(endpoint, status string) {
+	probeStats.Lock()
+	defer probeStats.Unlock()
+	probeStats.counts[probeKey{endpoint, status}]++
+}
+
+// recordUpstream keeps the outcome of the most recent upstream probe
+func recordUpstream(probe UpstreamProbe) {
+	probeStats.Lock()
+	defer probeStats.Unlock()
+	probeStats.upstreamLatency = time.Duration(probe.LatencyMS) * time.Millisecond
+	probeStats.upstreamUp = probe.Healthy()
+	probeStats.upstreamProbed = true
+}
+
+// readSyncState loads the counters written by github-sync.sh
+func readSyncState() (SyncState, error) {
+	var state SyncState
+	data, err := os.ReadFile(syncStateFile)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// jobRunStats aggregates finished runs of one job type
+type jobRunStats struct {
+	byStatus    map[string]uint64
+	count       uint64
+	durationSum float64
+}
+
+// readJobRuns aggregates the run log written by job-manager.sh, keyed by job type
+func readJobRuns() (map[string]*jobRunStats, error) {
+	stats := make(map[string]*jobRunStats)
+	file, err := os.Open(jobRunsFile)
+	if err != nil {
+		return stats, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var run JobStatus
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue
+		}
+		s, ok := stats[run.JobType]
+		if !ok {
+			s = &jobRunStats{byStatus: make(map[string]uint64)}
+			stats[run.JobType] = s
+		}
+		s.byStatus[run.Status]++
+		s.count++
+		s.durationSum += float64(run.DurationSeconds)
+	}
+	return stats, scanner.Err()
+}
+
+// metricsWriter emits the Prometheus text exposition format
+type metricsWriter struct {
+	w io.Writer
+}
+
+// family writes the HELP and TYPE lines for a metric
+func (m metricsWriter) family(name, typ, help string) {
+	fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one value; labels are given as alternating name/value pairs
+func (m metricsWriter) sample(name string, value float64, labels ...string) {
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", labels[i], strconv.Quote(labels[i+1])))
+		}
+		name += "{" + strings.Join(pairs, ",") + "}"
+	}
+	fmt.Fprintf(m.w, "%s %s\n", name, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// boolValue converts a boolean to a gauge value
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metricsHandler handles requests to the /metrics endpoint
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /metrics endpoint
+	if r.URL.Path != "/metrics" {
+		http.NotFound(w, r)
+		return
+	}
+
+	now := time.Now()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m := metricsWriter{w: w}
+
+	m.family("dev_health_uptime_seconds", "gauge", "Seconds since the dev health server started.")
+	m.sample("dev_health_uptime_seconds", now.Sub(processStart).Seconds())
+
+	writeProbeMetrics(m)
+	writeStartupMetrics(m, now)
+	writeSyncMetrics(m, now)
+	writeJobMetrics(m)
+	writeResourceMetrics(m)
+}
+
+// writeProbeMetrics reports health probes served and the last upstream probe
+func writeProbeMetrics(m metricsWriter) {
+	probeStats.Lock()
+	keys := make([]probeKey, 0, len(probeStats.counts))
+	counts := make(map[probeKey]uint64, len(probeStats.counts))
+	for key, count := range probeStats.counts {
+		keys = append(keys, key)
+		counts[key] = count
+	}
+	latency := probeStats.upstreamLatency
+	up := probeStats.upstreamUp
+	probed := probeStats.upstreamProbed
+	probeStats.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	m.family("dev_health_probes_total", "counter", "Health probes served, by endpoint and reported status.")
+	for _, key := range keys {
+		m.sample("dev_health_probes_total", float64(counts[key]), "endpoint", key.endpoint, "status", key.status)
+	}
+
+	if probed {
+		m.family("dev_health_upstream_up", "gauge", "Whether the last readiness probe of the app succeeded.")
+		m.sample("dev_health_upstream_up", boolValue(up))
+		m.family("dev_health_upstream_latency_seconds", "gauge", "Latency of the last readiness probe of the app.")
+		m.sample("dev_health_upstream_latency_seconds", latency.Seconds())
+	}
+}
+
+// writeStartupMetrics reports how long each startup phase took
+func writeStartupMetrics(m metricsWriter, now time.Time) {
+	startup, ok := readStartupStatus(now)
+	if !ok {
+		return
+	}
+
+	m.family("dev_startup_complete", "gauge", "Whether startup.sh reached its final phase.")
+	m.sample("dev_startup_complete", boolValue(startup.Complete))
+	m.family("dev_startup_phase_duration_seconds", "gauge", "Time spent in each startup phase; the current phase is still growing.")
+	for _, phase := range startup.Phases {
+		m.sample("dev_startup_phase_duration_seconds", phase.DurationSeconds, "phase", phase.Name)
+	}
+}
+
+// writeSyncMetrics reports github-sync.sh counters and the current commit age
+func writeSyncMetrics(m metricsWriter, now time.Time) {
+	state, err := readSyncState()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error reading sync state: %v", err)
+		}
+		return
+	}
+
+	m.family("dev_sync_attempts_total", "counter", "Sync cycles run by github-sync.sh.")
+	m.sample("dev_sync_attempts_total", float64(state.Attempts))
+	m.family("dev_sync_failures_total", "counter", "Sync cycles that failed to fetch, clone or sync.")
+	m.sample("dev_sync_failures_total", float64(state.Failures))
+
+	if t, err := time.Parse(time.RFC3339, state.LastSuccess); err == nil {
+		m.family("dev_sync_last_success_timestamp_seconds", "gauge", "Unix time of the last successful sync cycle.")
+		m.sample("dev_sync_last_success_timestamp_seconds", float64(t.Unix()))
+	}
+	if state.Commit != "" {
+		m.family("dev_sync_commit_info", "gauge", "The currently checked-out commit.")
+		m.sample("dev_sync_commit_info", 1, "commit", state.Commit)
+	}
+	if t, err := time.Parse(time.RFC3339, state.CommitTime); err == nil {
+		m.family("dev_sync_commit_age_seconds", "gauge", "Age of the currently checked-out commit.")
+		m.sample("dev_sync_commit_age_seconds", now.Sub(t).Seconds())
+	}
+}
+
+// writeJobMetrics reports deploy job run counts and durations
+func writeJobMetrics(m metricsWriter) {
+	runs, err := readJobRuns()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error reading job runs: %v", err)
+	}
+	last := readJobStatuses()
+
+	m.family("dev_deploy_job_runs_total", "counter", "Finished deploy job runs, by job and result.")
+	for _, jobType := range jobTypes {
+		if s, ok := runs[jobType]; ok {
+			for _, status := range []string{"success", "failed", "timeout"} {
+				m.sample("dev_deploy_job_runs_total", float64(s.byStatus[status]), "job", jobType, "status", status)
+			}
+		}
+	}
+
+	m.family("dev_deploy_job_duration_seconds", "summary", "Duration of finished deploy job runs.")
+	for _, jobType := range jobTypes {
+		if s, ok := runs[jobType]; ok {
+			m.sample("dev_deploy_job_duration_seconds_sum", s.durationSum, "job", jobType)
+			m.sample("dev_deploy_job_duration_seconds_count", float64(s.count), "job", jobType)
+		}
+	}
+
+	m.family("dev_deploy_job_running", "gauge", "Whether a deploy job is currently running.")
+	for _, jobType := range jobTypes {
+		if job, ok := last[jobType]; ok {
+			m.sample("dev_deploy_job_running", boolValue(job.Status == "running"), "job", jobType)
+		}
+	}
+
+	m.family("dev_deploy_job_last_success", "gauge", "Whether the last finished run of a deploy job succeeded.")
+	for _, jobType := range jobTypes {
+		if job, ok := last[jobType]; ok && job.Status != "running" {
+			m.sample("dev_deploy_job_last_success", boolValue(job.Status == "success"), "job", jobType)
+		}
+	}
+}
+
+// writeResourceMetrics reports disk space, memory use and OOM kills
+func writeResourceMetrics(m metricsWriter) {
+	status := readResourceStatus()
+
+	m.family("dev_disk_size_bytes", "gauge", "Size of the filesystem holding each path.")
+	for _, disk := range status.Disks {
+		if disk.Error == "" {
+			m.sample("dev_disk_size_bytes", float64(disk.TotalBytes), "path", disk.Path)
+		}
+	}
+	m.family("dev_disk_free_bytes", "gauge", "Space available to unprivileged users on the filesystem holding each path.")
+	for _, disk := range status.Disks {
+		if disk.Error == "" {
+			m.sample("dev_disk_free_bytes", float64(disk.FreeBytes), "path", disk.Path)
+		}
+	}
+
+	memory := status.Memory
+	if memory == nil {
+		return
+	}
+	m.family("dev_memory_usage_bytes", "gauge", "Memory used by the container, including page cache.")
+	m.sample("dev_memory_usage_bytes", float64(memory.UsageBytes))
+	m.family("dev_memory_working_set_bytes", "gauge", "Memory used by the container, excluding reclaimable page cache.")
+	m.sample("dev_memory_working_set_bytes", float64(memory.WorkingSetBytes))
+	if memory.LimitBytes > 0 {
+		m.family("dev_memory_limit_bytes", "gauge", "Memory limit of the container, or host memory when unlimited.")
+		m.sample("dev_memory_limit_bytes", float64(memory.LimitBytes))
+	}
+	if memory.Source == "cgroup" {
+		m.family("dev_memory_oom_events_total", "counter", "Times the container hit its memory limit and the OOM killer ran.")
+		m.sample("dev_memory_oom_events_total", float64(memory.OOMEvents))
+		m.family("dev_memory_oom_kills_total", "counter", "Processes killed by the OOM killer in this container.")
+		m.sample("dev_memory_oom_kills_total", float64(memory.OOMKills))
+	}
+}
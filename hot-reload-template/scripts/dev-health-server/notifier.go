@@ -0,0 +1,356 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	webhookStatusChanged  = "status_changed"
+	webhookJobFailed      = "job_failed"
+	webhookCommitDeployed = "commit_deployed"
+)
+
+// webhookQueueSize bounds undelivered events; when the endpoint is down for
+// long, newer events are dropped rather than buffering without limit
+const webhookQueueSize = 100
+
+// webhookInitialBackoff is doubled after each failed delivery attempt
+const webhookInitialBackoff = time.Second
+
+// WebhookEvent is the JSON body POSTed to DEV_HEALTH_WEBHOOK_URL
+type WebhookEvent struct {
+	Event     string     `json:"event"`
+	Timestamp string     `json:"timestamp"`
+	Service   string     `json:"service"`
+	Host      string     `json:"host,omitempty"`
+	Repo      string     `json:"repo,omitempty"`
+	Commit    string     `json:"commit,omitempty"`
+	From      string     `json:"from,omitempty"`
+	To        string     `json:"to,omitempty"`
+	Summary   string     `json:"summary"`
+	Failing   []string   `json:"failing_checks,omitempty"`
+	Job       *JobStatus `json:"job,omitempty"`
+}
+
+// slackPayload is the minimal body accepted by Slack-compatible incoming webhooks
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// webhookNotifier turns health transitions into webhook deliveries
+type webhookNotifier struct {
+	url     string
+	secret  string
+	format  string
+	retries int
+	host    string
+	client  *http.Client
+	queue   chan WebhookEvent
+
+	// Last observed state; only touched by the history evaluator goroutine
+	observed   bool
+	lastStatus string
+	lastCommit string
+	lastJobs   map[string]string
+}
+
+// notifier is nil unless DEV_HEALTH_WEBHOOK_URL is set
+var notifier *webhookNotifier
+
+// newWebhookNotifier starts the delivery worker for the configured URL
+func newWebhookNotifier(c Config) *webhookNotifier {
+	host, _ := os.Hostname()
+	n := &webhookNotifier{
+		url:      c.WebhookURL,
+		secret:   c.WebhookSecret,
+		format:   c.WebhookFormat,
+		retries:  c.WebhookRetries,
+		host:     host,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		queue:    make(chan WebhookEvent, webhookQueueSize),
+		lastJobs: make(map[string]string),
+	}
+	go n.deliverAll()
+	return n
+}
+
+// Observe compares a health report with the previous one and queues an event
+// for each transition. The first report only sets the baseline, so a restart
+// does not replay old failures.
+func (n *webhookNotifier) Observe(report HealthResponse) {
+	if n == nil {
+		return
+	}
+
+	commit := ""
+	if report.Sync != nil {
+		commit = report.Sync.Commit
+	}
+
+	if n.observed && report.Status != n.lastStatus {
+		event := n.newEvent(webhookStatusChanged, commit)
+		event.From, event.To = n.lastStatus, report.Status
+		for _, check := range report.Checks {
+			if check.Status == checkFail || check.Status == checkWarn {
+				event.Failing = append(event.Failing, check.Name)
+			}
+		}
+		event.Summary = fmt.Sprintf("Dev workspace is %s (was %s)", report.Status, n.lastStatus)
+		if report.Status == statusStarting && report.Phase != "" {
+			event.Summary += fmt.Sprintf(", phase %s", report.Phase)
+		}
+		if len(event.Failing) > 0 {
+			event.Summary += "; failing checks: " + strings.Join(event.Failing, ", ")
+		}
+		n.enqueue(event)
+	}
+	n.lastStatus = report.Status
+
+	if n.observed && commit != "" && commit != n.lastCommit {
+		event := n.newEvent(webhookCommitDeployed, commit)
+		event.From, event.To = shortCommit(n.lastCommit), shortCommit(commit)
+		event.Summary = fmt.Sprintf("Deployed commit %s", shortCommit(commit))
+		if report.Sync.Branch != "" {
+			event.Summary += fmt.Sprintf(" on %s", report.Sync.Branch)
+		}
+		n.enqueue(event)
+	}
+	if commit != "" {
+		n.lastCommit = commit
+	}
+
+	// A finished run is identified by its finish time; each failure is sent once
+	for jobType, job := range report.Jobs {
+		if job.FinishedAt == "" || job.FinishedAt == n.lastJobs[jobType] {
+			continue
+		}
+		if n.observed && job.Failed() {
+			job := job
+			event := n.newEvent(webhookJobFailed, job.Commit)
+			event.Job = &job
+			event.Summary = fmt.Sprintf("%s job %s", jobType, job.Status)
+			if job.ExitCode != nil {
+				event.Summary += fmt.Sprintf(" with exit code %d", *job.ExitCode)
+			}
+			if job.Commit != "" {
+				event.Summary += fmt.Sprintf(" for commit %s", shortCommit(job.Commit))
+			}
+			n.enqueue(event)
+		}
+		n.lastJobs[jobType] = job.FinishedAt
+	}
+
+	n.observed = true
+}
+
+// newEvent fills the fields common to every event
+func (n *webhookNotifier) newEvent(kind, commit string) WebhookEvent {
+	return WebhookEvent{
+		Event:     kind,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Service:   "dev-container",
+		Host:      n.host,
+		Repo:      redactURL(cfg.RepoURL),
+		Commit:    commit,
+	}
+}
+
+// enqueue hands an event to the delivery worker without blocking the evaluator
+func (n *webhookNotifier) enqueue(event WebhookEvent) {
+	select {
+	case n.queue <- event:
+	default:
+		log.Printf("Warning: Webhook queue full, dropping %s event", event.Event)
+	}
+}
+
+// deliverAll sends queued events one at a time, in order
+func (n *webhookNotifier) deliverAll() {
+	for event := range n.queue {
+		n.deliver(event)
+	}
+}
+
+// deliver POSTs one event, retrying network errors, 429 and 5xx responses
+// with exponential backoff
+func (n *webhookNotifier) deliver(event WebhookEvent) {
+	body, err := n.encode(event)
+	if err != nil {
+		log.Printf("Error encoding webhook event: %v", err)
+		return
+	}
+
+	backoff := webhookInitialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(event.Event, body)
+		if err == nil {
+			log.Printf("Webhook delivered: %s", event.Summary)
+			return
+		}
+		if !retry || attempt >= n.retries {
+			log.Printf("Error delivering %s webhook after %d attempt(s): %v", event.Event, attempt+1, err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// encode renders the event in the configured payload format
+func (n *webhookNotifier) encode(event WebhookEvent) ([]byte, error) {
+	if n.format == "slack" {
+		return json.Marshal(slackPayload{Text: slackText(event)})
+	}
+	return json.Marshal(event)
+}
+
+// post sends one request and reports whether a failure is worth retrying
+func (n *webhookNotifier) post(kind string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "dev-health-server")
+	req.Header.Set("X-Dev-Health-Event", kind)
+	if n.secret != "" {
+		req.Header.Set("X-Dev-Health-Signature", "sha256="+signPayload(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The URL itself is often the secret (Slack), so keep it out of logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// signPayload returns the hex HMAC-SHA256 of body, as GitHub webhooks do
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// slackText formats an event as a one-line chat message
+func slackText(event WebhookEvent) string {
+	icon := ":large_green_circle:"
+	switch {
+	case event.Event == webhookJobFailed || event.To == statusUnhealthy:
+		icon = ":red_circle:"
+	case event.Event == webhookStatusChanged && event.To != statusOK:
+		icon = ":large_yellow_circle:"
+	case event.Event == webhookCommitDeployed:
+		icon = ":rocket:"
+	}
+
+	text := fmt.Sprintf("%s %s", icon, event.Summary)
+	if event.Host != "" {
+		text += fmt.Sprintf(" (`%s`)", event.Host)
+	}
+	return text
+}
+
+// shortCommit abbreviates a commit hash for messages
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// webhookHost returns only the host of the webhook URL, for logging
+func webhookHost(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "(invalid URL)"
+}
+
+// redactURL drops credentials embedded in a repository URL. It works on the
+// raw string, since url.Parse misreads tokens holding an unescaped @ / ? or #
+// and would send them out: everything from the scheme to the last @ before
+// the host is dropped.
+func redactURL(raw string) string {
+	prefix, rest := "", raw
+	if i := strings.Index(raw, "://"); i >= 0 {
+		prefix, rest = raw[:i+3], raw[i+3:]
+	}
+	at := strings.LastIndex(rest[:authorityEnd(rest)], "@")
+	if at < 0 {
+		at = userinfoAt(rest)
+	}
+	if at < 0 {
+		return raw
+	}
+	return prefix + rest[at+1:]
+}
+
+// authorityEnd is the index of the first / ? or # in s, or len(s)
+func authorityEnd(s string) int {
+	if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// userinfoAt finds the @ ending a userinfo that holds an unescaped / ? or #,
+// which cut the authority short before its @. That is the case when what
+// precedes the first delimiter cannot be a host: "user:" followed by
+// something other than a port, or a bare token with no dot. The userinfo then
+// ends at the last @ before the host's first /. Returns -1 when there is none.
+func userinfoAt(rest string) int {
+	authority := rest[:authorityEnd(rest)]
+	if strings.HasPrefix(authority, "[") {
+		return -1
+	}
+	colon := strings.Index(authority, ":")
+	switch {
+	case colon >= 0 && isPortNumber(authority[colon+1:]):
+		return -1
+	case colon < 0 && (strings.Contains(authority, ".") || authority == "localhost"):
+		return -1
+	}
+	first := strings.Index(rest, "@")
+	if first < len(authority) {
+		return -1
+	}
+	hostEnd := len(rest)
+	if i := strings.Index(rest[first:], "/"); i >= 0 {
+		hostEnd = first + i
+	}
+	return strings.LastIndex(rest[:hostEnd], "@")
+}
+
+// isPortNumber reports whether s is empty or all digits, as a URL port is
+func isPortNumber(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
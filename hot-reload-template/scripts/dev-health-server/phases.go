@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// startupPhasesFile mirrors STARTUP_PHASES_FILE in startup.sh
+const startupPhasesFile = "/tmp/dev-startup-phases.jsonl"
+
+// finalPhases end the startup sequence: the app was exec'd, or there is no app to run
+var finalPhases = map[string]bool{"app": true, "no_app": true}
+
+// PhaseTiming describes one startup phase and how long it took
+type PhaseTiming struct {
+	Name            string  `json:"name"`
+	StartedAt       string  `json:"started_at"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Current         bool    `json:"current"`
+}
+
+// StartupStatus is the JSON response for the /dev_status endpoint
+type StartupStatus struct {
+	Phase          string        `json:"phase"`
+	Complete       bool          `json:"complete"`
+	StartedAt      string        `json:"started_at"`
+	StartupSeconds float64       `json:"startup_seconds"`
+	Phases         []PhaseTiming `json:"phases"`
+}
+
+// phaseRecord is one line of the phase log written by startup.sh
+type phaseRecord struct {
+	Phase     string `json:"phase"`
+	StartedAt string `json:"started_at"`
+}
+
+// readStartupStatus builds phase timings from the phase log. It returns
+// false when startup.sh has not written one (e.g. the server runs standalone).
+func readStartupStatus(now time.Time) (StartupStatus, bool) {
+	var status StartupStatus
+	file, err := os.Open(startupPhasesFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error reading startup phases: %v", err)
+		}
+		return status, false
+	}
+	defer file.Close()
+
+	var starts []time.Time
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record phaseRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		started, err := time.Parse(time.RFC3339Nano, record.StartedAt)
+		if err != nil {
+			continue
+		}
+		status.Phases = append(status.Phases, PhaseTiming{Name: record.Phase, StartedAt: record.StartedAt})
+		starts = append(starts, started)
+	}
+	if len(status.Phases) == 0 {
+		return status, false
+	}
+
+	// Each phase lasts until the next one starts; the last one is still running
+	last := len(status.Phases) - 1
+	for i := range status.Phases {
+		end := now
+		if i < last {
+			end = starts[i+1]
+		}
+		status.Phases[i].DurationSeconds = roundSeconds(end.Sub(starts[i]))
+	}
+	status.Phases[last].Current = true
+
+	status.Phase = status.Phases[last].Name
+	status.Complete = finalPhases[status.Phase]
+	status.StartedAt = status.Phases[0].StartedAt
+	if status.Complete {
+		status.StartupSeconds = roundSeconds(starts[last].Sub(starts[0]))
+	} else {
+		status.StartupSeconds = roundSeconds(now.Sub(starts[0]))
+	}
+	return status, true
+}
+
+// roundSeconds converts a duration to seconds with millisecond precision
+func roundSeconds(d time.Duration) float64 {
+	return float64(d.Milliseconds()) / 1000
+}
+
+// statusHandler handles requests to the /dev_status endpoint
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /dev_status endpoint
+	if r.URL.Path != "/dev_status" {
+		http.NotFound(w, r)
+		return
+	}
+
+	status, ok := readStartupStatus(time.Now())
+	if !ok {
+		http.Error(w, "startup phases not recorded", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
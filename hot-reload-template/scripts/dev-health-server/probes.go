@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// healthJSONType is the media type of the IETF health check response format
+const healthJSONType = "application/health+json"
+
+// probeCheck is one line of a /livez, /readyz or /startupz breakdown
+type probeCheck struct {
+	Name    string
+	Type    string
+	Status  string // checkPass, checkWarn or checkFail; only fail fails the probe
+	Message string
+	Time    string
+}
+
+// healthJSONResponse follows the "Health Check Response Format for HTTP APIs" draft
+type healthJSONResponse struct {
+	Status      string                       `json:"status"`
+	ServiceID   string                       `json:"serviceId"`
+	Description string                       `json:"description"`
+	Output      string                       `json:"output,omitempty"`
+	Checks      map[string][]healthJSONCheck `json:"checks"`
+}
+
+// healthJSONCheck is one entry of the health+json "checks" object
+type healthJSONCheck struct {
+	ComponentType string `json:"componentType,omitempty"`
+	Status        string `json:"status"`
+	Output        string `json:"output,omitempty"`
+	Time          string `json:"time,omitempty"`
+}
+
+// fromCheckResult maps a check to a probe line. A failing non-critical check
+// only warns, so it is listed without failing the probe.
+func fromCheckResult(check CheckResult) probeCheck {
+	probe := probeCheck{Name: check.Name, Type: check.Type, Status: check.Status, Message: check.Message, Time: check.CheckedAt}
+	switch {
+	case check.Status == checkPending && check.Critical:
+		probe.Status, probe.Message = checkFail, "not run yet"
+	case check.Status == checkPending:
+		probe.Status, probe.Message = checkWarn, "not run yet"
+	case check.Status == checkFail && !check.Critical:
+		probe.Status = checkWarn
+	}
+	return probe
+}
+
+// livezChecks reports whether the container needs a restart: the server
+// answers and the background processes startup.sh launched are still running.
+// The app itself is left to /readyz, since a crashing app is fixed by a push.
+func livezChecks(now time.Time) []probeCheck {
+	probes := []probeCheck{{Name: "ping", Status: checkPass}}
+	for _, process := range processChecks(readRecordedProcesses()) {
+		if process.Name == "process:app" {
+			continue
+		}
+		probes = append(probes, probeCheck{Name: process.Name, Type: process.Type, Status: process.Status, Message: process.Message, Time: process.CheckedAt})
+	}
+	return probes
+}
+
+// readyzChecks reports whether the container should receive traffic: startup
+// finished, the app answers, and no critical check is failing
+func readyzChecks(now time.Time) []probeCheck {
+	probes := []probeCheck{startupCheck(now)}
+
+	upstream := probeUpstream(cfg.ReadyTimeout)
+	recordUpstream(upstream)
+	app := probeCheck{Name: "app", Type: "http", Status: checkPass, Message: upstream.URL, Time: now.UTC().Format(time.RFC3339)}
+	if !upstream.Healthy() {
+		app.Status, app.Message = checkFail, upstream.Error
+	}
+	probes = append(probes, app)
+
+	if cfg.FailOnPreDeploy {
+		preDeploy := probeCheck{Name: "pre-deploy", Type: "job", Status: checkPass}
+		commit := ""
+		if cfg.RepoURL != "" {
+			commit = readSyncStatus(now).Commit
+		}
+		if jobs := readJobStatuses(); preDeployFailedForCommit(jobs, commit) {
+			preDeploy.Status = checkFail
+			preDeploy.Message = fmt.Sprintf("PRE_DEPLOY %s for commit %s", jobs["PRE_DEPLOY"].Status, shortCommit(jobs["PRE_DEPLOY"].Commit))
+		}
+		probes = append(probes, preDeploy)
+	}
+
+	for _, check := range append(checks.Results(), dropinChecks(now)...) {
+		probes = append(probes, fromCheckResult(check))
+	}
+	return probes
+}
+
+// startupzChecks lists each startup phase; the probe passes once a final phase
+// is reached, or immediately when the server runs without startup.sh
+func startupzChecks(now time.Time) []probeCheck {
+	startup, ok := readStartupStatus(now)
+	if !ok {
+		return []probeCheck{startupCheck(now)}
+	}
+
+	probes := make([]probeCheck, 0, len(startup.Phases))
+	for _, phase := range startup.Phases {
+		probe := probeCheck{
+			Name:    "phase:" + phase.Name,
+			Type:    "startup",
+			Status:  checkPass,
+			Message: fmt.Sprintf("%.1fs", phase.DurationSeconds),
+			Time:    phase.StartedAt,
+		}
+		if phase.Current && !startup.Complete {
+			probe.Status = checkFail
+			probe.Message = fmt.Sprintf("in progress for %.1fs", phase.DurationSeconds)
+		}
+		if phase.Current && startup.Complete {
+			probe.Message = fmt.Sprintf("reached after %.1fs", startup.StartupSeconds)
+		}
+		probes = append(probes, probe)
+	}
+	return probes
+}
+
+// startupCheck summarises startup as a single line
+func startupCheck(now time.Time) probeCheck {
+	probe := probeCheck{Name: "startup", Type: "startup", Status: checkPass}
+	startup, ok := readStartupStatus(now)
+	switch {
+	case !ok:
+		probe.Message = "no startup phases recorded"
+	case !startup.Complete:
+		probe.Status = checkFail
+		probe.Message = fmt.Sprintf("in phase %s", startup.Phase)
+	}
+	return probe
+}
+
+// probeHandler serves a Kubernetes-style probe endpoint. Query parameters:
+// ?verbose lists every check, ?exclude=<name> (repeatable) ignores a check.
+// Clients sending Accept: application/health+json get the IETF JSON format.
+func probeHandler(name string, collect func(time.Time) []probeCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Only respond to the probe's own path
+		if r.URL.Path != "/"+name {
+			http.NotFound(w, r)
+			return
+		}
+
+		now := time.Now()
+		query := r.URL.Query()
+		excluded := make(map[string]bool)
+		for _, exclude := range query["exclude"] {
+			excluded[exclude] = true
+		}
+
+		var included []probeCheck
+		failed, warned := false, false
+		for _, check := range collect(now) {
+			if excluded[check.Name] {
+				continue
+			}
+			included = append(included, check)
+			failed = failed || check.Status == checkFail
+			warned = warned || check.Status == checkWarn
+		}
+
+		statusCode := http.StatusOK
+		status := checkPass
+		switch {
+		case failed:
+			statusCode = http.StatusServiceUnavailable
+			status = checkFail
+		case warned:
+			status = checkWarn
+		}
+		recordProbe(name, status)
+		w.Header().Set("Vary", "Accept")
+
+		if strings.Contains(r.Header.Get("Accept"), healthJSONType) {
+			writeHealthJSON(w, name, status, statusCode, included)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(statusCode)
+
+		if !query.Has("verbose") {
+			if failed {
+				fmt.Fprintf(w, "%s check failed\n", name)
+			} else {
+				fmt.Fprintln(w, "ok")
+			}
+			return
+		}
+
+		for _, check := range included {
+			mark, result := "+", "ok"
+			switch check.Status {
+			case checkWarn:
+				result = "warn"
+			case checkFail:
+				mark, result = "-", "failed"
+			}
+			if check.Message != "" {
+				result += ": " + check.Message
+			}
+			fmt.Fprintf(w, "[%s]%s %s\n", mark, check.Name, strings.ReplaceAll(result, "\n", " "))
+		}
+		if failed {
+			fmt.Fprintf(w, "%s check failed\n", name)
+		} else {
+			fmt.Fprintf(w, "%s check passed\n", name)
+		}
+	}
+}
+
+// writeHealthJSON encodes a probe result as application/health+json
+func writeHealthJSON(w http.ResponseWriter, name, status string, statusCode int, included []probeCheck) {
+	response := healthJSONResponse{
+		Status:      status,
+		ServiceID:   "dev-container",
+		Description: name,
+		Checks:      make(map[string][]healthJSONCheck, len(included)),
+	}
+	if status == checkFail {
+		response.Output = fmt.Sprintf("%s check failed", name)
+	}
+	for _, check := range included {
+		response.Checks[check.Name] = append(response.Checks[check.Name], healthJSONCheck{
+			ComponentType: check.Type,
+			Status:        check.Status,
+			Output:        check.Message,
+			Time:          check.Time,
+		})
+	}
+
+	w.Header().Set("Content-Type", healthJSONType)
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pidDir mirrors PID_DIR in startup.sh
+const pidDir = "/tmp/dev-pids"
+
+// clockTicks is USER_HZ, the unit of CPU and start times in /proc/<pid>/stat.
+// It is 100 on every Linux architecture App Platform runs on.
+const clockTicks = 100
+
+// ProcessInfo describes one container process read from /proc
+type ProcessInfo struct {
+	Name          string        `json:"name,omitempty"`
+	PID           int           `json:"pid"`
+	Running       bool          `json:"running"`
+	Command       string        `json:"command,omitempty"`
+	State         string        `json:"state,omitempty"`
+	UptimeSeconds int64         `json:"uptime_seconds,omitempty"`
+	RSSBytes      int64         `json:"rss_bytes,omitempty"`
+	CPUSeconds    float64       `json:"cpu_seconds,omitempty"`
+	Children      []ProcessInfo `json:"children,omitempty"`
+}
+
+// ProcessInventory is the JSON response for the /dev_processes endpoint
+type ProcessInventory struct {
+	Timestamp string        `json:"timestamp"`
+	Processes []ProcessInfo `json:"processes"`
+	Missing   []string      `json:"missing"`
+}
+
+// procStat holds the /proc/<pid>/stat fields the inventory needs
+type procStat struct {
+	state     string
+	ppid      int
+	cpuTicks  int64
+	startTick int64
+	rssPages  int64
+}
+
+// readProcStat parses /proc/<pid>/stat; the command name may contain
+// spaces and parentheses, so fields are counted from the last ")"
+func readProcStat(pid int) (procStat, error) {
+	var stat procStat
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return stat, err
+	}
+	end := strings.LastIndexByte(string(data), ')')
+	if end < 0 {
+		return stat, errors.New("malformed stat")
+	}
+	// fields[0] is field 3 (state) in proc(5) numbering
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return stat, errors.New("malformed stat")
+	}
+	stat.state = fields[0]
+	stat.ppid, _ = strconv.Atoi(fields[1])
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	stat.cpuTicks = utime + stime
+	stat.startTick, _ = strconv.ParseInt(fields[19], 10, 64)
+	stat.rssPages, _ = strconv.ParseInt(fields[21], 10, 64)
+	return stat, nil
+}
+
+// systemUptime returns seconds since boot from /proc/uptime
+func systemUptime() float64 {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0
+	}
+	uptime, _ := strconv.ParseFloat(fields[0], 64)
+	return uptime
+}
+
+// readProcess describes a live process; Running is false if it has exited
+// (zombies count as exited)
+func readProcess(pid int, uptime float64) ProcessInfo {
+	info := ProcessInfo{PID: pid}
+	stat, err := readProcStat(pid)
+	if err != nil || stat.state == "Z" {
+		return info
+	}
+
+	info.Running = true
+	info.State = stat.state
+	info.CPUSeconds = float64(stat.cpuTicks) / clockTicks
+	info.RSSBytes = stat.rssPages * int64(os.Getpagesize())
+	if uptime > 0 {
+		info.UptimeSeconds = int64(uptime - float64(stat.startTick)/clockTicks)
+	}
+	if cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+		info.Command = strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+	}
+	return info
+}
+
+// childrenByParent maps each PID to the PIDs of its direct children
+func childrenByParent() map[int][]int {
+	children := make(map[int][]int)
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return children
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		if stat, err := readProcStat(pid); err == nil {
+			children[stat.ppid] = append(children[stat.ppid], pid)
+		}
+	}
+	return children
+}
+
+// descendants lists every live process below pid, depth first
+func descendants(pid int, tree map[int][]int, uptime float64) []ProcessInfo {
+	var result []ProcessInfo
+	for _, child := range tree[pid] {
+		if info := readProcess(child, uptime); info.Running {
+			result = append(result, info)
+		}
+		result = append(result, descendants(child, tree, uptime)...)
+	}
+	return result
+}
+
+// readProcessInventory reports every process startup.sh recorded a PID for,
+// with the processes the app spawned. A recorded process that is no longer
+// running is listed as missing.
+func readProcessInventory() ProcessInventory {
+	inventory := readRecordedProcesses()
+	for i, process := range inventory.Processes {
+		// The app is usually a wrapper script; show what it spawned. Finding
+		// children means reading every /proc entry, so only this endpoint does it.
+		if process.Name == "app" && process.Running {
+			inventory.Processes[i].Children = descendants(process.PID, childrenByParent(), systemUptime())
+		}
+	}
+	return inventory
+}
+
+// readRecordedProcesses reads only the processes named by the PID files, so
+// /dev_health can check them without walking /proc
+func readRecordedProcesses() ProcessInventory {
+	inventory := ProcessInventory{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Processes: []ProcessInfo{},
+		Missing:   []string{},
+	}
+
+	pidFiles, err := filepath.Glob(filepath.Join(pidDir, "*.pid"))
+	if err != nil || len(pidFiles) == 0 {
+		// Running outside startup.sh: report just this server
+		self := readProcess(os.Getpid(), systemUptime())
+		self.Name = "dev-health-server"
+		inventory.Processes = append(inventory.Processes, self)
+		return inventory
+	}
+	sort.Strings(pidFiles)
+
+	uptime := systemUptime()
+	for _, pidFile := range pidFiles {
+		name := strings.TrimSuffix(filepath.Base(pidFile), ".pid")
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			continue
+		}
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			log.Printf("Invalid PID file %s", pidFile)
+			continue
+		}
+
+		info := readProcess(pid, uptime)
+		info.Name = name
+		if !info.Running {
+			inventory.Missing = append(inventory.Missing, name)
+		}
+		inventory.Processes = append(inventory.Processes, info)
+	}
+	return inventory
+}
+
+// processChecks reports each recorded process as a non-critical check so a
+// dead sync loop degrades /dev_health instead of going unnoticed
+func processChecks(inventory ProcessInventory) []CheckResult {
+	results := make([]CheckResult, 0, len(inventory.Processes))
+	for _, process := range inventory.Processes {
+		result := CheckResult{
+			Name:      "process:" + process.Name,
+			Type:      "process",
+			Status:    checkPass,
+			Message:   fmt.Sprintf("PID %d", process.PID),
+			CheckedAt: inventory.Timestamp,
+		}
+		if !process.Running {
+			result.Status = checkFail
+			result.Message = fmt.Sprintf("PID %d is no longer running", process.PID)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// processesHandler handles requests to the /dev_processes endpoint
+func processesHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /dev_processes endpoint
+	if r.URL.Path != "/dev_processes" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(readProcessInventory()); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
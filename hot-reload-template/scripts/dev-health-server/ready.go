@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReadyResponse represents the JSON response for the readiness endpoint
+type ReadyResponse struct {
+	Status    string        `json:"status"`
+	Service   string        `json:"service"`
+	Timestamp string        `json:"timestamp"`
+	Upstream  UpstreamProbe `json:"upstream"`
+}
+
+// UpstreamProbe describes the result of probing the user application
+type UpstreamProbe struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Healthy reports whether the upstream answered with a 2xx or 3xx status
+func (p UpstreamProbe) Healthy() bool {
+	return p.Error == "" && p.StatusCode >= 200 && p.StatusCode < 400
+}
+
+// probeUpstream requests the app's health path on the app port and records the outcome
+func probeUpstream(timeout time.Duration) UpstreamProbe {
+	path := cfg.AppHealthPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	probe := UpstreamProbe{URL: fmt.Sprintf("http://127.0.0.1:%d%s", cfg.AppPort, path)}
+
+	client := &http.Client{
+		Timeout: timeout,
+		// Redirects count as a live app; don't chase them off-host
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	resp, err := client.Get(probe.URL)
+	probe.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	defer resp.Body.Close()
+
+	probe.StatusCode = resp.StatusCode
+	if !probe.Healthy() {
+		probe.Error = fmt.Sprintf("upstream returned %s", resp.Status)
+	}
+	return probe
+}
+
+// readyHandler handles requests to the /dev_ready endpoint
+func readyHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /dev_ready endpoint
+	if r.URL.Path != "/dev_ready" {
+		http.NotFound(w, r)
+		return
+	}
+
+	probe := probeUpstream(cfg.ReadyTimeout)
+	recordUpstream(probe)
+
+	response := ReadyResponse{
+		Status:    "ok",
+		Service:   "dev-container",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Upstream:  probe,
+	}
+	statusCode := http.StatusOK
+	if !probe.Healthy() {
+		response.Status = "unavailable"
+		statusCode = http.StatusServiceUnavailable
+	}
+	recordProbe("dev_ready", response.Status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// cgroupDir is where cgroup v2 exposes the container's own memory controller
+const cgroupDir = "/sys/fs/cgroup"
+
+// resourceSampleInterval is how often memory.events is polled for OOM kills
+const resourceSampleInterval = 10 * time.Second
+
+// oomWarnWindow is how long a detected OOM kill keeps the memory check at warn
+const oomWarnWindow = 15 * time.Minute
+
+// maxOOMEvents bounds the recent OOM kill list
+const maxOOMEvents = 20
+
+// DiskUsage is the free space of the filesystem holding Path
+type DiskUsage struct {
+	Path        string  `json:"path"`
+	TotalBytes  uint64  `json:"total_bytes"`
+	FreeBytes   uint64  `json:"free_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// MemoryUsage is the container's memory use, read from cgroup v2 when
+// available and from /proc/meminfo otherwise
+type MemoryUsage struct {
+	Source string `json:"source"`
+	// UsageBytes includes page cache; WorkingSetBytes excludes reclaimable
+	// inactive file pages and is what the kernel's OOM decision tracks
+	UsageBytes      uint64  `json:"usage_bytes"`
+	WorkingSetBytes uint64  `json:"working_set_bytes"`
+	LimitBytes      uint64  `json:"limit_bytes,omitempty"`
+	UsedPercent     float64 `json:"used_percent,omitempty"`
+	OOMEvents       uint64  `json:"oom_events"`
+	OOMKills        uint64  `json:"oom_kills"`
+}
+
+// OOMKill records an increase of the cgroup oom_kill counter
+type OOMKill struct {
+	DetectedAt string `json:"detected_at"`
+	Kills      uint64 `json:"kills"`
+	// BeforeStart marks kills that happened before this server was running
+	BeforeStart bool `json:"before_start,omitempty"`
+}
+
+// ResourceStatus is the resources section of the health report
+type ResourceStatus struct {
+	Disks    []DiskUsage  `json:"disks"`
+	Memory   *MemoryUsage `json:"memory,omitempty"`
+	OOMKills []OOMKill    `json:"recent_oom_kills,omitempty"`
+}
+
+// resourceMonitor samples the cgroup OOM counters in the background so that
+// kills are timestamped even when nobody is polling /dev_health
+type resourceMonitor struct {
+	mu        sync.Mutex
+	sampled   bool
+	lastKills uint64
+	events    []OOMKill
+}
+
+// resources is the process-wide resource monitor, started in main
+var resources = &resourceMonitor{}
+
+// Watch records OOM kills until ctx is cancelled
+func (r *resourceMonitor) Watch(ctx context.Context) {
+	ticker := time.NewTicker(resourceSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		r.sample(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample compares the oom_kill counter with the previous reading
+func (r *resourceMonitor) sample(now time.Time) {
+	events, err := readCgroupKeyValues("memory.events")
+	if err != nil {
+		return
+	}
+	kills := events["oom_kill"]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if kills > r.lastKills {
+		event := OOMKill{
+			DetectedAt:  now.UTC().Format(time.RFC3339),
+			Kills:       kills - r.lastKills,
+			BeforeStart: !r.sampled,
+		}
+		r.events = append(r.events, event)
+		if len(r.events) > maxOOMEvents {
+			r.events = r.events[len(r.events)-maxOOMEvents:]
+		}
+		if !event.BeforeStart {
+			log.Printf("Warning: %d process(es) OOM-killed in this container", event.Kills)
+		}
+	}
+	r.lastKills = kills
+	r.sampled = true
+}
+
+// Events returns the recent OOM kills, oldest first
+func (r *resourceMonitor) Events() []OOMKill {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]OOMKill(nil), r.events...)
+}
+
+// readResourceStatus reads disk and memory usage for the health report
+func readResourceStatus() ResourceStatus {
+	status := ResourceStatus{OOMKills: resources.Events()}
+	for _, path := range []string{cfg.WorkspacePath, "/tmp"} {
+		if len(status.Disks) > 0 && status.Disks[0].Path == path {
+			continue
+		}
+		status.Disks = append(status.Disks, readDiskUsage(path))
+	}
+	if memory, err := readMemoryUsage(); err == nil {
+		status.Memory = &memory
+	}
+	return status
+}
+
+// readDiskUsage reports space available to unprivileged users, as df does
+func readDiskUsage(path string) DiskUsage {
+	usage := DiskUsage{Path: path}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		usage.Error = err.Error()
+		return usage
+	}
+
+	blockSize := uint64(st.Bsize)
+	used := (uint64(st.Blocks) - uint64(st.Bfree)) * blockSize
+	usage.TotalBytes = uint64(st.Blocks) * blockSize
+	usage.FreeBytes = uint64(st.Bavail) * blockSize
+	if used+usage.FreeBytes > 0 {
+		usage.UsedPercent = roundPercent(float64(used) / float64(used+usage.FreeBytes))
+	}
+	return usage
+}
+
+// readMemoryUsage prefers the cgroup v2 controller, falling back to host memory
+func readMemoryUsage() (MemoryUsage, error) {
+	current, err := readCgroupUint("memory.current")
+	if err != nil {
+		return readHostMemory()
+	}
+
+	usage := MemoryUsage{Source: "cgroup", UsageBytes: current, WorkingSetBytes: current}
+	if stat, err := readCgroupKeyValues("memory.stat"); err == nil && stat["inactive_file"] < current {
+		usage.WorkingSetBytes = current - stat["inactive_file"]
+	}
+	if events, err := readCgroupKeyValues("memory.events"); err == nil {
+		usage.OOMEvents = events["oom"]
+		usage.OOMKills = events["oom_kill"]
+	}
+
+	// memory.max is "max" when the container has no limit
+	limit, err := readCgroupUint("memory.max")
+	if err != nil {
+		if host, err := readHostMemory(); err == nil {
+			limit = host.LimitBytes
+		}
+	}
+	if limit > 0 {
+		usage.LimitBytes = limit
+		usage.UsedPercent = roundPercent(float64(usage.WorkingSetBytes) / float64(limit))
+	}
+	return usage, nil
+}
+
+// readHostMemory reads total and available memory from /proc/meminfo
+func readHostMemory() (MemoryUsage, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return MemoryUsage{}, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if kb, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			values[key] = kb * 1024
+		}
+	}
+	if values["MemTotal"] == 0 {
+		return MemoryUsage{}, fmt.Errorf("no MemTotal in /proc/meminfo")
+	}
+
+	used := values["MemTotal"] - values["MemAvailable"]
+	return MemoryUsage{
+		Source:          "host",
+		UsageBytes:      used,
+		WorkingSetBytes: used,
+		LimitBytes:      values["MemTotal"],
+		UsedPercent:     roundPercent(float64(used) / float64(values["MemTotal"])),
+	}, nil
+}
+
+// readCgroupUint reads a single-number cgroup file such as memory.current
+func readCgroupUint(name string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(cgroupDir, name))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readCgroupKeyValues reads a flat-keyed cgroup file such as memory.events
+func readCgroupKeyValues(name string) (map[string]uint64, error) {
+	data, err := os.ReadFile(filepath.Join(cgroupDir, name))
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values, nil
+}
+
+// roundPercent converts a ratio to a percentage with one decimal place
+func roundPercent(ratio float64) float64 {
+	return float64(int64(ratio*1000+0.5)) / 10
+}
+
+// thresholdStatus maps a usage percentage to pass, warn or fail
+func thresholdStatus(percent float64, warn, fail int) string {
+	switch {
+	case percent >= float64(fail):
+		return checkFail
+	case percent >= float64(warn):
+		return checkWarn
+	}
+	return checkPass
+}
+
+// resourceChecks reports disk and memory pressure as non-critical checks:
+// a full disk or memory limit degrades the container but restarting it
+// would not help
+func resourceChecks(status ResourceStatus, now time.Time) []CheckResult {
+	checkedAt := now.UTC().Format(time.RFC3339)
+	var results []CheckResult
+
+	for _, disk := range status.Disks {
+		result := CheckResult{Name: "disk:" + disk.Path, Type: "disk", CheckedAt: checkedAt}
+		if disk.Error != "" {
+			result.Status = checkFail
+			result.Message = disk.Error
+		} else {
+			result.Status = thresholdStatus(disk.UsedPercent, cfg.DiskWarnPercent, cfg.DiskFailPercent)
+			result.Message = fmt.Sprintf("%.1f%% used, %s free", disk.UsedPercent, formatBytes(disk.FreeBytes))
+		}
+		results = append(results, result)
+	}
+
+	if memory := status.Memory; memory != nil {
+		result := CheckResult{Name: "memory", Type: "memory", Status: checkPass, CheckedAt: checkedAt}
+		if memory.LimitBytes > 0 {
+			result.Status = thresholdStatus(memory.UsedPercent, cfg.MemoryWarnPercent, cfg.MemoryFailPercent)
+			result.Message = fmt.Sprintf("%.1f%% of %s used", memory.UsedPercent, formatBytes(memory.LimitBytes))
+		} else {
+			result.Message = fmt.Sprintf("%s used", formatBytes(memory.WorkingSetBytes))
+		}
+
+		// A recent OOM kill means something already died, even if usage
+		// has dropped again since. Kills from before this server started
+		// are stamped with the first sample's time, so they do not count.
+		var recent uint64
+		for _, event := range status.OOMKills {
+			if event.BeforeStart {
+				continue
+			}
+			if t, err := time.Parse(time.RFC3339, event.DetectedAt); err == nil && now.Sub(t) < oomWarnWindow {
+				recent += event.Kills
+			}
+		}
+		if recent > 0 {
+			if result.Status == checkPass {
+				result.Status = checkWarn
+			}
+			result.Message += fmt.Sprintf("; %d OOM kill(s) in the last %s", recent, oomWarnWindow)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// formatBytes renders a byte count in binary units
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResourceChecksRecentOOMKills(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	stamp := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+	tests := []struct {
+		name   string
+		kills  []OOMKill
+		status string
+		want   string
+	}{
+		{"none", nil, checkPass, ""},
+		{"before start only", []OOMKill{{DetectedAt: stamp(time.Minute), Kills: 3, BeforeStart: true}}, checkPass, ""},
+		{"recent", []OOMKill{{DetectedAt: stamp(time.Minute), Kills: 2}}, checkWarn, "; 2 OOM kill(s)"},
+		{"recent after pre-start kills", []OOMKill{
+			{DetectedAt: stamp(2 * time.Minute), Kills: 3, BeforeStart: true},
+			{DetectedAt: stamp(time.Minute), Kills: 1},
+		}, checkWarn, "; 1 OOM kill(s)"},
+		{"outside window", []OOMKill{{DetectedAt: stamp(oomWarnWindow + time.Minute), Kills: 1}}, checkPass, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := ResourceStatus{Memory: &MemoryUsage{WorkingSetBytes: 1 << 20}, OOMKills: tt.kills}
+			results := resourceChecks(status, now)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Status != tt.status {
+				t.Errorf("status = %q, want %q (%s)", result.Status, tt.status, result.Message)
+			}
+			if tt.want == "" && strings.Contains(result.Message, "OOM") || !strings.Contains(result.Message, tt.want) {
+				t.Errorf("message = %q, want %q", result.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// monorepoCacheDir mirrors MONOREPO_CACHE in github-sync.sh
+const monorepoCacheDir = "/tmp/monorepo-cache"
+
+// SyncStatus describes the state of the synced repository
+type SyncStatus struct {
+	RepoPath            string `json:"repo_path"`
+	Commit              string `json:"commit,omitempty"`
+	Branch              string `json:"branch,omitempty"`
+	LastFetch           string `json:"last_fetch,omitempty"`
+	LastFetchAgeSeconds int64  `json:"last_fetch_age_seconds"`
+	Stale               bool   `json:"stale"`
+	Error               string `json:"error,omitempty"`
+}
+
+// Healthy reports whether the repository is present and recently fetched
+func (s SyncStatus) Healthy() bool {
+	return s.Error == "" && !s.Stale
+}
+
+// repoPath returns the git checkout github-sync.sh keeps up to date.
+// In monorepo mode that is the cache clone, not the rsynced workspace.
+func repoPath() string {
+	if cfg.RepoFolder != "" && cfg.RepoURL != "" {
+		// Matches get_repo_hash: `echo "$url" | md5sum` hashes the trailing newline too
+		sum := md5.Sum([]byte(cfg.RepoURL + "\n"))
+		return filepath.Join(monorepoCacheDir, hex.EncodeToString(sum[:]))
+	}
+	return cfg.WorkspacePath
+}
+
+// readSyncStatus inspects the repository on disk without shelling out to git
+func readSyncStatus(now time.Time) SyncStatus {
+	status := SyncStatus{RepoPath: repoPath()}
+	gitDir := filepath.Join(status.RepoPath, ".git")
+
+	commit, branch, err := readHead(gitDir)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Commit = commit
+	status.Branch = branch
+
+	// FETCH_HEAD is rewritten by every successful `git fetch`; a fresh clone
+	// has none yet, so fall back to the HEAD reflog written by the clone.
+	var lastFetch time.Time
+	for _, name := range []string{"FETCH_HEAD", filepath.Join("logs", "HEAD")} {
+		if info, err := os.Stat(filepath.Join(gitDir, name)); err == nil {
+			lastFetch = info.ModTime()
+			break
+		}
+	}
+	if lastFetch.IsZero() {
+		status.Error = "no fetch recorded"
+		return status
+	}
+
+	age := now.Sub(lastFetch)
+	status.LastFetch = lastFetch.UTC().Format(time.RFC3339)
+	status.LastFetchAgeSeconds = int64(age.Seconds())
+	status.Stale = age > cfg.SyncInterval*time.Duration(cfg.SyncStaleFactor)
+	return status
+}
+
+// readHead resolves HEAD to a commit SHA and, when on a branch, its name
+func readHead(gitDir string) (commit, branch string, err error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", "", errors.New("repository not cloned")
+		}
+		return "", "", err
+	}
+
+	head := strings.TrimSpace(string(data))
+	ref, ok := strings.CutPrefix(head, "ref: ")
+	if !ok {
+		// Detached HEAD holds the SHA directly
+		return head, "", nil
+	}
+
+	branch = strings.TrimPrefix(ref, "refs/heads/")
+	commit, err = resolveRef(gitDir, ref)
+	return commit, branch, err
+}
+
+// resolveRef looks a ref up as a loose file first, then in packed-refs
+func resolveRef(gitDir, ref string) (string, error) {
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	file, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s", ref)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		if sha, name, ok := strings.Cut(line, " "); ok && name == ref {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("cannot resolve %s", ref)
+}
@@ -0,0 +1,104 @@
+#!/usr/bin/env bash
+# Dev Log Capture
+# Copies stdin to stdout (so output still reaches the container log) and appends
+# each line, with a UTC timestamp, to a rotating file read by the welcome page
+# server's log viewer
+#
+# USAGE:
+#   some-command 2>&1 | dev-log-capture SOURCE
+#   some-command > >(dev-log-capture SOURCE) 2>&1
+#
+# Lines tagged [PRE_DEPLOY] or [POST_DEPLOY] by job-manager.sh go to the "jobs"
+# file whichever stream they arrive on, so job output nested in the sync
+# output is not stored twice.
+#
+# ENVIRONMENT:
+#   DEV_LOG_DIR        Directory for <source>.log files (default: /tmp/dev-logs)
+#   DEV_LOG_MAX_BYTES  Rotate a file once it grows past this size (default: 5 MB)
+#   DEV_LOG_FILES      Rotated files to keep per source (default: 3)
+
+set -uo pipefail
+
+LOG_DIR="${DEV_LOG_DIR:-/tmp/dev-logs}"
+MAX_BYTES="${DEV_LOG_MAX_BYTES:-5242880}"
+KEEP_FILES="${DEV_LOG_FILES:-3}"
+
+# Timestamps come from printf's built-in strftime, which follows TZ
+export TZ=UTC
+
+JOB_LINE=$'^(\e\\[[0-9;]*m)?\\[(PRE_DEPLOY|POST_DEPLOY)\\]'
+
+usage() {
+    echo "Usage: $0 SOURCE" >&2
+    exit 1
+}
+
+# Current size of a file in bytes (0 when missing)
+file_size() {
+    if [ -f "$1" ]; then
+        wc -c < "$1"
+    else
+        echo 0
+    fi
+}
+
+# Shift SOURCE.log -> SOURCE.log.1 -> ... and drop the oldest
+rotate() {
+    local file="$1"
+    local i
+    for ((i = KEEP_FILES - 1; i >= 1; i--)); do
+        [ -f "$file.$i" ] && mv -f "$file.$i" "$file.$((i + 1))"
+    done
+    if [ "$KEEP_FILES" -ge 1 ]; then
+        mv -f "$file" "$file.1" 2>/dev/null
+    else
+        rm -f "$file"
+    fi
+}
+
+main() {
+    [ $# -eq 1 ] || usage
+    local source="$1"
+    if ! [[ "$source" =~ ^[A-Za-z0-9_-]+$ ]]; then
+        echo "Invalid source '$source' (use letters, digits, _ and -)" >&2
+        exit 1
+    fi
+    if ! [[ "$MAX_BYTES" =~ ^[0-9]+$ ]] || ! [[ "$KEEP_FILES" =~ ^[0-9]+$ ]]; then
+        echo "DEV_LOG_MAX_BYTES and DEV_LOG_FILES must be whole numbers" >&2
+        exit 1
+    fi
+
+    mkdir -p "$LOG_DIR" 2>/dev/null
+
+    # Sizes are tracked in memory to avoid a stat per line; other writers to
+    # the same file are picked up when the estimate says it is time to rotate
+    declare -A sizes
+    local line target stamp entry
+    while IFS= read -r line || [ -n "$line" ]; do
+        printf '%s\n' "$line"
+
+        target="$source"
+        if [[ "$line" =~ $JOB_LINE ]]; then
+            target="jobs"
+        fi
+        local file="$LOG_DIR/$target.log"
+        if [ -z "${sizes[$target]:-}" ]; then
+            sizes[$target]=$(file_size "$file")
+        fi
+
+        printf -v stamp '%(%Y-%m-%dT%H:%M:%SZ)T' -1
+        entry="$stamp $line"
+        printf '%s\n' "$entry" >> "$file" 2>/dev/null || continue
+        sizes[$target]=$((sizes[$target] + ${#entry} + 1))
+
+        if [ "${sizes[$target]}" -gt "$MAX_BYTES" ]; then
+            sizes[$target]=$(file_size "$file")
+            if [ "${sizes[$target]}" -gt "$MAX_BYTES" ]; then
+                rotate "$file"
+                sizes[$target]=0
+            fi
+        fi
+    done
+}
+
+main "$@"
@@ -21,6 +21,17 @@ REPO_FOLDER="${GITHUB_REPO_FOLDER:-}"
 REPO_BRANCH="${GITHUB_BRANCH:-}"
 MONOREPO_CACHE="/tmp/monorepo-cache"
 
+# Sync state (read by dev-health-server for metrics)
+SYNC_STATE_FILE="/tmp/dev-sync-state.json"
+SYNC_ATTEMPTS=0
+SYNC_FAILURES=0
+LAST_SYNC_ATTEMPT=""
+LAST_SYNC_SUCCESS=""
+SYNC_REQUESTED=0
+
+# Sync event log (read by welcome-page-server for its live activity feed)
+SYNC_EVENTS_FILE="/tmp/dev-sync-events.jsonl"
+
 # Colors for output
 RED='\033[0;31m'
 GREEN='\033[0;32m'
@@ -101,7 +112,7 @@ get_auth_url() {
 }
 
 # Check if remote has new commits (fetch + compare)
-# Returns 0 if changes detected, 1 if no changes
+# Returns 0 if changes detected, 1 if no changes, 2 if fetch failed
 # Sets REMOTE_COMMIT variable with the remote commit SHA
 check_for_changes() {
     local git_dir="$1"
@@ -112,7 +123,7 @@ check_for_changes() {
     # Fetch latest refs (lightweight operation)
     if ! git fetch origin 2>&1; then
         log_error "Failed to fetch from remote"
-        return 1
+        return 2
     fi
 
     # Get current local commit
@@ -137,6 +148,7 @@ check_for_changes() {
         return 1  # No changes
     else
         log_info "Changes detected: ${local_commit:0:7} -> ${REMOTE_COMMIT:0:7}"
+        record_sync_event "commit_detected" "$REMOTE_COMMIT" "$(git log -1 --pretty=%s "$REMOTE_COMMIT" 2>/dev/null || echo "")"
         return 0  # Changes detected
     fi
 }
@@ -229,7 +241,23 @@ cleanup_lock_files() {
     done
 }
 
-# Show current commit info
+# Escape a string for use inside a JSON string literal
+json_escape() {
+    local s="$1"
+    s="${s//\\/\\\\}"
+    s="${s//\"/\\\"}"
+    printf '%s' "$s" | tr -d '\000-\037'
+}
+
+# Append one event to $SYNC_EVENTS_FILE; a failed write never stops the sync
+# Args: $1=event (commit_detected|pulled|failed), $2=commit SHA, $3=message
+record_sync_event() {
+    printf '{"event":"%s","commit":"%s","message":"%s","time":"%s"}\n' \
+        "$1" "${2:-}" "$(json_escape "${3:-}")" "$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+        >> "$SYNC_EVENTS_FILE" 2>/dev/null || true
+}
+
+# Show current commit info and record it as pulled
 show_commit_info() {
     local git_dir="$1"
 
@@ -238,6 +266,7 @@ show_commit_info() {
         local commit=$(git rev-parse --short HEAD 2>/dev/null || echo "unknown")
         local msg=$(git log -1 --pretty=%B 2>/dev/null | head -1 || echo "")
         log_info "Current commit: $commit - $msg"
+        record_sync_event "pulled" "$(git rev-parse HEAD 2>/dev/null || echo "")" "$msg"
     fi
 }
 
@@ -331,7 +360,12 @@ sync_repo() {
         fi
 
         # Check for changes (fetch + compare commits)
-        if check_for_changes "$cache_dir" "$REPO_BRANCH"; then
+        local changes=0
+        check_for_changes "$cache_dir" "$REPO_BRANCH" || changes=$?
+        if [ $changes -eq 2 ]; then
+            return 1
+        fi
+        if [ $changes -eq 0 ]; then
             # Changes detected - pull and sync
             log_info "Pulling changes..."
             pull_changes "$cache_dir" "$REPO_BRANCH"
@@ -372,7 +406,12 @@ sync_repo() {
         fi
 
         # Check for changes (fetch + compare commits)
-        if check_for_changes "$WORKSPACE" "$REPO_BRANCH"; then
+        local changes=0
+        check_for_changes "$WORKSPACE" "$REPO_BRANCH" || changes=$?
+        if [ $changes -eq 2 ]; then
+            return 1
+        fi
+        if [ $changes -eq 0 ]; then
             # Changes detected - pull
             log_info "Pulling changes..."
             pull_changes "$WORKSPACE" "$REPO_BRANCH"
@@ -385,9 +424,60 @@ sync_repo() {
     fi
 }
 
+# Write sync counters and current commit to $SYNC_STATE_FILE
+# Written via temp file + rename so readers never see a partial file
+write_sync_state() {
+    local git_dir="$WORKSPACE"
+    if [ -n "$REPO_FOLDER" ] && [ -n "$REPO_URL" ]; then
+        git_dir="$MONOREPO_CACHE/$(get_repo_hash "$REPO_URL")"
+    fi
+
+    local commit=""
+    local commit_time=""
+    if [ -d "$git_dir/.git" ]; then
+        commit=$(git -C "$git_dir" rev-parse HEAD 2>/dev/null || echo "")
+        commit_time=$(git -C "$git_dir" log -1 --format=%cI 2>/dev/null || echo "")
+    fi
+
+    printf '{"attempts":%d,"failures":%d,"last_attempt":"%s","last_success":"%s","commit":"%s","commit_time":"%s"}\n' \
+        "$SYNC_ATTEMPTS" "$SYNC_FAILURES" "$LAST_SYNC_ATTEMPT" "$LAST_SYNC_SUCCESS" "$commit" "$commit_time" \
+        > "${SYNC_STATE_FILE}.tmp"
+    mv -f "${SYNC_STATE_FILE}.tmp" "$SYNC_STATE_FILE"
+}
+
+# Run one sync cycle and record its outcome
+# A failed cycle is logged and retried; it never stops the sync loop.
+# sync_repo runs in a subshell with errexit on: called as an `if` condition
+# or with `||`, bash would ignore set -e inside it, and a failed cd, git or
+# rsync halfway through would count as a successful sync. For the same reason
+# run_sync itself must not be called as a condition.
+run_sync() {
+    SYNC_ATTEMPTS=$((SYNC_ATTEMPTS + 1))
+    LAST_SYNC_ATTEMPT=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+
+    local status
+    set +e
+    (set -e; sync_repo)
+    status=$?
+    set -e
+
+    if [ "$status" -eq 0 ]; then
+        LAST_SYNC_SUCCESS="$LAST_SYNC_ATTEMPT"
+    else
+        SYNC_FAILURES=$((SYNC_FAILURES + 1))
+        record_sync_event "failed" "" "Sync failed (${SYNC_FAILURES} of ${SYNC_ATTEMPTS} attempts)"
+        log_warn "Sync failed (${SYNC_FAILURES} of ${SYNC_ATTEMPTS} attempts). Retrying in ${SYNC_INTERVAL}s."
+    fi
+
+    write_sync_state || log_warn "Failed to write sync state to $SYNC_STATE_FILE"
+}
+
 # Main sync loop
 main() {
     log_info "GitHub Sync Service Starting..."
+
+    # SIGUSR1 (sent by the welcome page "Sync now" button) ends the wait early
+    trap 'SYNC_REQUESTED=1' USR1
     log_info "Sync interval: ${SYNC_INTERVAL}s"
 
     # Display monorepo configuration if enabled
@@ -399,16 +489,32 @@ main() {
     fi
 
     # Initial sync (always runs on startup)
-    sync_repo
+    run_sync
 
     # Continuous sync loop
     while true; do
         log_info "Waiting ${SYNC_INTERVAL}s before next sync..."
-        sleep "$SYNC_INTERVAL"
-        sync_repo
+        wait_for_next_sync
+        run_sync
     done
 }
 
+# Sleep for SYNC_INTERVAL unless a sync is requested with SIGUSR1
+# The sleep runs in the background because bash only runs traps between
+# commands, and `wait` is interrupted by a trapped signal while `sleep` is not
+wait_for_next_sync() {
+    if [ "$SYNC_REQUESTED" -eq 0 ]; then
+        sleep "$SYNC_INTERVAL" &
+        local sleep_pid=$!
+        wait "$sleep_pid" 2>/dev/null || true
+        kill "$sleep_pid" 2>/dev/null || true
+    fi
+    if [ "$SYNC_REQUESTED" -eq 1 ]; then
+        log_info "Sync requested; syncing now"
+        SYNC_REQUESTED=0
+    fi
+}
+
 # Create or update monorepo cache (exported for use by startup.sh)
 # This function ensures the cache exists and is up to date
 create_or_update_monorepo_cache() {
@@ -24,6 +24,9 @@ POST_DEPLOY_TIMEOUT="${POST_DEPLOY_TIMEOUT:-300}"
 
 # Tracking files
 LAST_JOB_COMMIT_FILE="/tmp/last_job_commit.txt"
+JOB_STATE_DIR="/tmp/dev-jobs"
+JOB_RUNS_FILE="$JOB_STATE_DIR/runs.jsonl"
+JOB_RUNS_KEEP=1000
 JOB_REPOS_DIR="/tmp/job-repos"
 MONOREPO_CACHE="/tmp/monorepo-cache"
 
@@ -52,6 +55,14 @@ log_job_error() {
     echo -e "${RED}[${job_type}]${NC} $*"
 }
 
+# Prefix each line read from stdin with [JOB_TYPE]
+prefix_output() {
+    local line
+    while IFS= read -r line || [ -n "$line" ]; do
+        printf '[%s] %s\n' "$1" "$line"
+    done
+}
+
 # Generate unique hash for repo URL
 get_repo_hash() {
     echo "$1" | md5sum | cut -d' ' -f1
@@ -115,6 +126,63 @@ update_last_job_commit() {
     fi
 }
 
+# Format epoch seconds as RFC3339 UTC (empty input yields empty output)
+format_timestamp() {
+    [ -n "$1" ] && date -u -d "@$1" +%Y-%m-%dT%H:%M:%SZ
+}
+
+# Write machine-readable job result to $JOB_STATE_DIR/<JOB_TYPE>.json
+# Finished runs are also appended to $JOB_RUNS_FILE (one JSON object per line)
+# and the file is capped by trim_job_runs
+# Read by dev-health-server to report job status and metrics
+# Args: $1=job_type, $2=status (running|success|failed|timeout), $3=exit_code,
+#       $4=started_at (epoch), $5=finished_at (epoch, empty while running)
+write_job_state() {
+    local job_type="$1"
+    local status="$2"
+    local exit_code="${3:-null}"
+    local started_at="$4"
+    local finished_at="${5:-}"
+    local commit=$(get_current_commit_sha)
+
+    local duration=0
+    local timed_out=false
+    if [ -n "$finished_at" ]; then
+        duration=$((finished_at - started_at))
+    fi
+    if [ "$status" = "timeout" ]; then
+        timed_out=true
+    fi
+
+    mkdir -p "$JOB_STATE_DIR"
+
+    # Write to temp file then rename so readers never see a partial file
+    local state_file="$JOB_STATE_DIR/${job_type}.json"
+    local record=$(printf '{"job_type":"%s","commit":"%s","status":"%s","exit_code":%s,"timed_out":%s,"started_at":"%s","finished_at":"%s","duration_seconds":%s}' \
+        "$job_type" "$commit" "$status" "$exit_code" "$timed_out" \
+        "$(format_timestamp "$started_at")" "$(format_timestamp "$finished_at")" "$duration")
+
+    echo "$record" > "${state_file}.tmp"
+    mv -f "${state_file}.tmp" "$state_file"
+
+    if [ "$status" != "running" ]; then
+        echo "$record" >> "$JOB_RUNS_FILE"
+        trim_job_runs
+    fi
+}
+
+# Keep the last $JOB_RUNS_KEEP runs once $JOB_RUNS_FILE holds more than twice that
+# The run counters in /metrics are read from this file, so trimming in one
+# large step makes them drop rarely, which Prometheus reads as a counter reset
+trim_job_runs() {
+    local lines=$(wc -l 2>/dev/null < "$JOB_RUNS_FILE" || echo 0)
+    if [ "$lines" -le $((JOB_RUNS_KEEP * 2)) ]; then
+        return 0
+    fi
+    tail -n "$JOB_RUNS_KEEP" "$JOB_RUNS_FILE" > "${JOB_RUNS_FILE}.tmp" \
+        && mv -f "${JOB_RUNS_FILE}.tmp" "$JOB_RUNS_FILE" || true
+}
+
 # Clone or update job repository (for multi-repo pattern)
 # Args: $1=repo_url, $2=repo_dir
 clone_or_update_job_repo() {
@@ -172,11 +240,45 @@ clone_or_update_job_repo() {
     fi
 }
 
-# Execute job (PRE_DEPLOY or POST_DEPLOY)
+# Execute job (PRE_DEPLOY or POST_DEPLOY) and record its result
 # Args: $1=JOB_TYPE ("PRE_DEPLOY" or "POST_DEPLOY")
 execute_job() {
     local job_type="$1"
 
+    if [ "$job_type" != "PRE_DEPLOY" ] && [ "$job_type" != "POST_DEPLOY" ]; then
+        log_job_error "$job_type" "Unknown job type: $job_type"
+        return 1
+    fi
+
+    local started_at=$(date +%s)
+    write_job_state "$job_type" "running" "" "$started_at" ""
+
+    JOB_EXIT_CODE=0
+    local result=0
+    run_job "$job_type" || result=$?
+
+    # Setup failures (missing repo/folder) never reach the command itself
+    if [ $result -ne 0 ] && [ "$JOB_EXIT_CODE" -eq 0 ]; then
+        JOB_EXIT_CODE=$result
+    fi
+
+    local status="success"
+    if [ "$JOB_EXIT_CODE" -eq 124 ]; then
+        status="timeout"
+    elif [ "$JOB_EXIT_CODE" -ne 0 ]; then
+        status="failed"
+    fi
+    write_job_state "$job_type" "$status" "$JOB_EXIT_CODE" "$started_at" "$(date +%s)"
+
+    return $result
+}
+
+# Run the job command in its execution directory
+# Sets JOB_EXIT_CODE to the command's exit code (124 on timeout)
+# Args: $1=JOB_TYPE ("PRE_DEPLOY" or "POST_DEPLOY")
+run_job() {
+    local job_type="$1"
+
     # Determine job configuration based on type
     local job_repo_url=""
     local job_folder=""
@@ -273,11 +375,13 @@ execute_job() {
     local exit_code=0
     cd "$job_exec_dir"
 
-    if timeout "${job_timeout}" bash -c "$job_command" 2>&1; then
+    # Tag command output with the job type so dev-log-capture files it under "jobs"
+    if timeout "${job_timeout}" bash -c "$job_command" 2>&1 | prefix_output "$job_type"; then
         log_job "$job_type" "Job completed successfully"
         return 0
     else
         exit_code=$?
+        JOB_EXIT_CODE=$exit_code
 
         if [ $exit_code -eq 124 ]; then
             log_job_error "$job_type" "Job timed out after ${job_timeout}s"
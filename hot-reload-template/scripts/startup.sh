@@ -4,6 +4,42 @@
 
 set -euo pipefail
 
+# Startup phase log (read by dev-health-server for /dev_status)
+STARTUP_PHASES_FILE="/tmp/dev-startup-phases.jsonl"
+: > "$STARTUP_PHASES_FILE"
+
+# Record the start of a startup phase with a millisecond UTC timestamp
+record_phase() {
+    printf '{"phase":"%s","started_at":"%s"}\n' "$1" "$(date -u +%Y-%m-%dT%H:%M:%S.%3NZ)" >> "$STARTUP_PHASES_FILE"
+}
+
+# PID files for background processes (read by dev-health-server for /dev_processes)
+PID_DIR="/tmp/dev-pids"
+rm -rf "$PID_DIR"
+mkdir -p "$PID_DIR"
+
+# Record the PID of a container process
+# Args: $1=name, $2=pid
+record_pid() {
+    echo "$2" > "$PID_DIR/$1.pid"
+}
+
+# Log capture: app, sync and job output is also written to rotating files in
+# /tmp/dev-logs for the welcome page log viewer; it still reaches the container log
+ENABLE_LOG_CAPTURE="${ENABLE_LOG_CAPTURE:-true}"
+
+# Read stdin into the log file for a source (pass-through when capture is off)
+# Args: $1=source
+capture_log() {
+    if [ "$ENABLE_LOG_CAPTURE" = "true" ]; then
+        /usr/local/bin/dev-log-capture "$1"
+    else
+        cat
+    fi
+}
+
+record_phase "init"
+
 echo "=========================================="
 echo "Dev Environment Starting..."
 echo "=========================================="
@@ -38,12 +74,41 @@ command -v mongosh &>/dev/null && echo "  ✓ MongoDB"
 command -v mysql &>/dev/null && echo "  ✓ MySQL"
 echo ""
 
+# Proxy mode: the welcome page server keeps port 8080 and forwards to the app
+# on an internal port, showing a status page while the app starts or restarts
+ENABLE_WELCOME_PROXY="${ENABLE_WELCOME_PROXY:-false}"
+WELCOME_PROXY_APP_PORT="${WELCOME_PROXY_APP_PORT:-3000}"
+if [ "$ENABLE_WELCOME_PROXY" = "true" ]; then
+    # Health checks should see the app itself, not the proxy's status page
+    DEV_HEALTH_APP_PORT="${DEV_HEALTH_APP_PORT:-$WELCOME_PROXY_APP_PORT}"
+else
+    DEV_HEALTH_APP_PORT="${DEV_HEALTH_APP_PORT:-8080}"
+fi
+
+# Start dev health check server (built-in Go binary) unless disabled
+# Started before the initial sync so it can report startup phases
+ENABLE_DEV_HEALTH="${ENABLE_DEV_HEALTH:-true}"
+DEV_HEALTH_PORT="${DEV_HEALTH_PORT:-9090}"
+if [ "$ENABLE_DEV_HEALTH" = "true" ]; then
+    echo "Starting dev health check server..."
+    DEV_HEALTH_PORT="$DEV_HEALTH_PORT" DEV_HEALTH_APP_PORT="$DEV_HEALTH_APP_PORT" /usr/local/bin/dev-health-server &
+    HEALTH_PID=$!
+    record_pid "dev-health-server" "$HEALTH_PID"
+    echo "✓ Dev health check server started (PID: $HEALTH_PID) - endpoints: /dev_health, /dev_status on port $DEV_HEALTH_PORT"
+    echo ""
+else
+    echo "Skipping dev health check server (ENABLE_DEV_HEALTH=$ENABLE_DEV_HEALTH)"
+    echo "Ensure your application exposes its own health endpoint per your App Spec."
+    echo ""
+fi
+
 echo "=========================================="
 echo "Starting GitHub Sync Service..."
 echo "=========================================="
 echo ""
 
 # Do initial sync first (blocking)
+record_phase "clone"
 echo "Performing initial repository sync..."
 REPO_URL="${GITHUB_REPO_URL:-}"
 AUTH_TOKEN="${GITHUB_TOKEN:-}"
@@ -90,6 +155,7 @@ echo ""
 # Create monorepo cache if needed (for apps in monorepo subfolders)
 # This must happen BEFORE DEV_START_COMMAND runs so dev_startup.sh is available
 if [ -n "${GITHUB_REPO_FOLDER:-}" ]; then
+    record_phase "monorepo_cache"
     echo "=========================================="
     echo "Preparing Monorepo Subfolder..."
     echo "=========================================="
@@ -119,12 +185,13 @@ fi
 
 # Execute PRE_DEPLOY job if configured (initial bootstrap)
 if [ -n "${PRE_DEPLOY_COMMAND:-}" ]; then
+    record_phase "pre_deploy"
     echo "=========================================="
     echo "Executing Initial PRE_DEPLOY Job..."
     echo "=========================================="
     echo ""
 
-    if /usr/local/bin/job-manager.sh execute PRE_DEPLOY; then
+    if /usr/local/bin/job-manager.sh execute PRE_DEPLOY > >(capture_log jobs) 2>&1; then
         echo "✓ Initial PRE_DEPLOY job completed successfully"
     else
         echo "ERROR: Initial PRE_DEPLOY job failed. Container cannot start."
@@ -134,35 +201,28 @@ if [ -n "${PRE_DEPLOY_COMMAND:-}" ]; then
 fi
 
 # Start GitHub sync loop in background (continuous polling)
+record_phase "sync_start"
 echo "Starting continuous sync service..."
-/usr/local/bin/github-sync.sh &
+/usr/local/bin/github-sync.sh > >(capture_log sync) 2>&1 &
 GITHUB_SYNC_PID=$!
+record_pid "github-sync" "$GITHUB_SYNC_PID"
 echo "✓ GitHub sync service started (PID: $GITHUB_SYNC_PID)"
 echo ""
 
-# Start dev health check server (built-in Go binary) unless disabled
-ENABLE_DEV_HEALTH="${ENABLE_DEV_HEALTH:-true}"
-DEV_HEALTH_PORT="${DEV_HEALTH_PORT:-9090}"
-if [ "$ENABLE_DEV_HEALTH" = "true" ]; then
-    echo "Starting dev health check server..."
-    DEV_HEALTH_PORT="$DEV_HEALTH_PORT" /usr/local/bin/dev-health-server &
-    HEALTH_PID=$!
-    echo "✓ Dev health check server started (PID: $HEALTH_PID) - endpoint: /dev_health on port $DEV_HEALTH_PORT"
-    echo ""
-else
-    echo "Skipping dev health check server (ENABLE_DEV_HEALTH=$ENABLE_DEV_HEALTH)"
-    echo "Ensure your application exposes its own health endpoint per your App Spec."
-    echo ""
-fi
-
 # Start welcome page server (built-in Go binary) on port 8080
-# This will automatically stop when the user's app starts via DEV_START_COMMAND
+# Unless it runs as a proxy, it is stopped when the user's app starts via DEV_START_COMMAND
 WELCOME_PAGE_PORT="${WELCOME_PAGE_PORT:-8080}"
 echo "Starting welcome page server..."
-WELCOME_PAGE_PORT="$WELCOME_PAGE_PORT" /usr/local/bin/welcome-page-server &
+WELCOME_PAGE_PORT="$WELCOME_PAGE_PORT" ENABLE_WELCOME_PROXY="$ENABLE_WELCOME_PROXY" \
+    WELCOME_PROXY_APP_PORT="$WELCOME_PROXY_APP_PORT" /usr/local/bin/welcome-page-server &
 WELCOME_PID=$!
+record_pid "welcome-page-server" "$WELCOME_PID"
 echo "✓ Welcome page server started (PID: $WELCOME_PID) - endpoint: / on port $WELCOME_PAGE_PORT"
-echo "  (Will automatically stop when your application starts)"
+if [ "$ENABLE_WELCOME_PROXY" = "true" ]; then
+    echo "  (Proxy mode: forwards to your application on port $WELCOME_PROXY_APP_PORT)"
+else
+    echo "  (Will automatically stop when your application starts)"
+fi
 echo ""
 
 # Determine workspace path
@@ -186,13 +246,18 @@ fi
 
 if [ -n "${DEV_START_COMMAND:-}" ]; then
     echo "Executing DEV_START_COMMAND: $DEV_START_COMMAND"
-    echo "Note: Welcome page server will be stopped when your app starts on port 8080"
     cd "$WORKSPACE"
     
-    # Stop welcome page server (since app will use port 8080)
-    if [ -n "${WELCOME_PID:-}" ]; then
+    if [ "$ENABLE_WELCOME_PROXY" = "true" ]; then
+        # The welcome page server keeps port 8080; the app listens on $PORT behind it
+        echo "Note: Your app must listen on \$PORT ($WELCOME_PROXY_APP_PORT); port 8080 is served by the welcome page proxy"
+        export PORT="$WELCOME_PROXY_APP_PORT"
+    elif [ -n "${WELCOME_PID:-}" ]; then
+        # Stop welcome page server (since app will use port 8080)
+        echo "Note: Welcome page server will be stopped when your app starts on port 8080"
         echo "Stopping welcome page server (PID: $WELCOME_PID) to free port 8080 for your app..."
         kill "$WELCOME_PID" 2>/dev/null || true
+        rm -f "$PID_DIR/welcome-page-server.pid"
         sleep 1  # Give the OS time to release the port before app starts
     fi
     
@@ -227,13 +292,22 @@ if [ -n "${DEV_START_COMMAND:-}" ]; then
     # Execute POST_DEPLOY job in background (initial bootstrap)
     if [ -n "${POST_DEPLOY_COMMAND:-}" ]; then
         echo "Executing Initial POST_DEPLOY Job in background..."
-        (/usr/local/bin/job-manager.sh execute POST_DEPLOY &)
+        (/usr/local/bin/job-manager.sh execute POST_DEPLOY > >(capture_log jobs) 2>&1 &)
         echo ""
     fi
 
+    # Capture the app's output from here on; unbuffered Python output keeps the log live
+    if [ "$ENABLE_LOG_CAPTURE" = "true" ]; then
+        export PYTHONUNBUFFERED="${PYTHONUNBUFFERED:-1}"
+        exec > >(capture_log app) 2>&1
+    fi
+
     # Execute command with environment loaded
+    record_phase "app"
+    record_pid "app" "$$"  # exec keeps this shell's PID
     exec bash -c "${ENV_SETUP}${DEV_START_COMMAND}"
 else
+    record_phase "no_app"
     echo "=========================================="
     echo "No Application Command Configured"
     echo "=========================================="
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"welcome-page-server/appspeclint"
+)
+
+// AppSpecReport is the JSON response for /api/appspec: the linter's findings
+// for the app spec committed to the workspace
+type AppSpecReport struct {
+	File     string                `json:"file,omitempty"`
+	Summary  map[string]int        `json:"summary"`
+	Findings []appspeclint.Finding `json:"findings"`
+	Error    string                `json:"error,omitempty"`
+}
+
+// lintAppSpec lints the first app spec found in the workspace. Dockerfile
+// paths are checked against the repository checkout, for components that
+// build from GITHUB_REPO_URL.
+func lintAppSpec(src configSource) AppSpecReport {
+	report := AppSpecReport{
+		Summary:  map[string]int{appspeclint.SeverityError: 0, appspeclint.SeverityWarning: 0, appspeclint.SeverityInfo: 0},
+		Findings: []appspeclint.Finding{},
+	}
+	for _, name := range appspeclint.SpecFiles {
+		path := filepath.Join(src.workspace, name)
+		if !fileExists(path) {
+			continue
+		}
+		report.File = name
+
+		opts := appspeclint.Options{Repo: src.get("GITHUB_REPO_URL")}
+		if checkout := repoCheckoutPath(src); dirExists(checkout) {
+			opts.RepoRoot = checkout
+		}
+		findings, err := appspeclint.LintFile(path, opts)
+		if err != nil {
+			report.Error = redactText(err.Error())
+			return report
+		}
+		for _, finding := range findings {
+			// Messages may quote values from the spec, so mask credentials in them
+			finding.Message = redactText(finding.Message)
+			finding.Hint = redactText(finding.Hint)
+			report.Findings = append(report.Findings, finding)
+			report.Summary[finding.Severity]++
+		}
+		return report
+	}
+	report.Error = fmt.Sprintf("No app spec found in the workspace (looked for %s).", strings.Join(appspeclint.SpecFiles, ", "))
+	return report
+}
+
+// appSpecHandler handles requests to the /api/appspec endpoint
+func appSpecHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/appspec endpoint
+	if r.URL.Path != "/api/appspec" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(lintAppSpec(currentConfig())); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
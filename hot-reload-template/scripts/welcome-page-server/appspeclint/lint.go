@@ -0,0 +1,325 @@
+// Package appspeclint checks an App Platform app spec for the mistakes that
+// break hot-reload-template deploys: settings in the wrong scope, a health
+// check nothing answers, http_port not reaching the welcome page or app, and
+// a dockerfile_path that does not exist. Each rule mirrors how startup.sh and
+// the Dockerfile consume the setting, and every finding carries the line of
+// the spec it is about.
+package appspeclint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities, most severe first
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Finding is one problem in the spec with a suggested fix
+type Finding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Component string `json:"component,omitempty"`
+	Setting   string `json:"setting,omitempty"`
+	Message   string `json:"message"`
+	Hint      string `json:"hint"`
+}
+
+// Options tell the linter where the spec's source code is
+type Options struct {
+	// RepoRoot is a checkout of the repository the spec builds from, used to
+	// check dockerfile_path. Empty skips the check.
+	RepoRoot string
+	// Repo is RepoRoot's repository, as owner/name or a clone URL. Components
+	// building from a different repository, such as the template repository
+	// when the spec lives in an app repository, are not checked against
+	// RepoRoot; with no Repo only components that name no repository are.
+	Repo string
+}
+
+// SpecFiles are the places a repository usually keeps its App Platform spec,
+// in the order tools look for one
+var SpecFiles = []string{".do/app.yaml", ".do/deploy.template.yaml", "appspec.yaml", "app.yaml"}
+
+// componentKinds are the spec sections whose components run the Dockerfile
+var componentKinds = []string{"services", "workers", "jobs"}
+
+// yamlLinePattern finds the line number in a yaml.v3 error message
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// LintFile reads and lints the spec at path
+func LintFile(path string, opts Options) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Lint(data, opts), nil
+}
+
+// Lint parses spec and applies every rule, returning findings in line order
+func Lint(spec []byte, opts Options) []Finding {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		line := 0
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return []Finding{{
+			Rule:     "yaml-syntax",
+			Severity: SeverityError,
+			Line:     line,
+			Message:  fmt.Sprintf("The spec is not valid YAML: %s.", strings.TrimPrefix(err.Error(), "yaml: ")),
+			Hint:     "Fix the YAML; App Platform rejects the whole spec.",
+		}}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		line := 1
+		if len(doc.Content) > 0 {
+			line = doc.Content[0].Line
+		}
+		return []Finding{{
+			Rule:     "not-a-spec",
+			Severity: SeverityError,
+			Line:     line,
+			Message:  "The file is not an app spec; its top level must be a mapping with name, services and so on.",
+			Hint:     "Start from one of the appspec.yaml files in hot-reload-template/app-examples.",
+		}}
+	}
+
+	root := doc.Content[0]
+	// Deploy-to-DigitalOcean templates nest the spec under "spec:"
+	if spec := mapValue(root, "spec"); spec != nil && spec.Kind == yaml.MappingNode {
+		root = spec
+	}
+
+	appEnvs := parseEnvs(mapValue(root, "envs"))
+	var findings []Finding
+	findings = append(findings, checkEnvList(appEnvs, "")...)
+	for _, kind := range componentKinds {
+		list := mapValue(root, kind)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, node := range list.Content {
+			if node.Kind != yaml.MappingNode {
+				continue
+			}
+			c := newComponent(kind, i, node, appEnvs)
+			for _, rule := range componentRules {
+				findings = append(findings, rule(c, opts)...)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// envVar is one entry of an envs list
+type envVar struct {
+	Key   string
+	Value string
+	Scope string
+	Type  string
+	entry *yaml.Node
+	value *yaml.Node
+	scope *yaml.Node
+}
+
+// effectiveScope is the scope App Platform applies, RUN_AND_BUILD_TIME when unset
+func (e envVar) effectiveScope() string {
+	if e.Scope == "" {
+		return "RUN_AND_BUILD_TIME"
+	}
+	return e.Scope
+}
+
+// parseEnvs reads an envs sequence, skipping entries without a key
+func parseEnvs(list *yaml.Node) []envVar {
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var envs []envVar
+	for _, entry := range list.Content {
+		if entry.Kind != yaml.MappingNode {
+			continue
+		}
+		env := envVar{
+			Key:   scalar(mapValue(entry, "key")),
+			Scope: scalar(mapValue(entry, "scope")),
+			Type:  scalar(mapValue(entry, "type")),
+			entry: entry,
+			value: mapValue(entry, "value"),
+			scope: mapValue(entry, "scope"),
+		}
+		if env.Key == "" {
+			continue
+		}
+		env.Value = scalar(env.value)
+		envs = append(envs, env)
+	}
+	return envs
+}
+
+// component is one service, worker or job
+type component struct {
+	Kind    string
+	Name    string
+	node    *yaml.Node
+	envs    []envVar
+	appEnvs []envVar
+}
+
+// newComponent reads the parts of a component the rules need
+func newComponent(kind string, index int, node *yaml.Node, appEnvs []envVar) *component {
+	c := &component{Kind: kind, node: node, appEnvs: appEnvs}
+	c.Name = scalar(mapValue(node, "name"))
+	if c.Name == "" {
+		c.Name = fmt.Sprintf("%s[%d]", kind, index)
+	}
+	c.envs = parseEnvs(mapValue(node, "envs"))
+	return c
+}
+
+// env returns the variable the component sees: its own, else the app-level one
+func (c *component) env(key string) (envVar, bool) {
+	for _, envs := range [][]envVar{c.envs, c.appEnvs} {
+		for i := len(envs) - 1; i >= 0; i-- {
+			if envs[i].Key == key {
+				return envs[i], true
+			}
+		}
+	}
+	return envVar{}, false
+}
+
+// setting returns the value startup.sh sees at run time, or def when the
+// spec does not set it or only sets it at build time
+func (c *component) setting(key, def string) string {
+	env, ok := c.env(key)
+	if !ok || env.effectiveScope() == "BUILD_TIME" || env.Value == "" {
+		return def
+	}
+	return env.Value
+}
+
+// field returns a value of the component's mapping
+func (c *component) field(path ...string) *yaml.Node {
+	node := c.node
+	for _, key := range path {
+		node = mapValue(node, key)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// usesTemplate reports whether the component runs the hot-reload template,
+// judged by its Dockerfile or the settings only the template reads
+func (c *component) usesTemplate() bool {
+	if strings.HasSuffix(scalar(c.field("dockerfile_path")), "hot-reload-template/Dockerfile") {
+		return true
+	}
+	for _, key := range []string{"GITHUB_REPO_URL", "DEV_START_COMMAND", "ENABLE_DEV_HEALTH"} {
+		if _, ok := c.env(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// finding builds a finding about the component, placed at node
+func (c *component) finding(node *yaml.Node, rule, severity, setting, message, hint string) Finding {
+	if node == nil {
+		node = c.node
+	}
+	return Finding{
+		Rule:      rule,
+		Severity:  severity,
+		Line:      node.Line,
+		Column:    node.Column,
+		Component: c.Name,
+		Setting:   setting,
+		Message:   message,
+		Hint:      hint,
+	}
+}
+
+// mapValue returns the value for key in a mapping node, or nil
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalar returns a scalar node's value, or "" for anything else
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+		return ""
+	}
+	return node.Value
+}
+
+// fileExists reports whether path is a regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// RepoName reduces a repository URL or a spec's repo setting to lowercase
+// owner/name, for comparing with Options.Repo
+func RepoName(repo string) string {
+	repo = strings.TrimSpace(strings.ToLower(repo))
+	if i := strings.Index(repo, "://"); i >= 0 {
+		repo = repo[i+3:]
+		if at := strings.LastIndex(repo, "@"); at >= 0 {
+			repo = repo[at+1:]
+		}
+		if _, rest, ok := strings.Cut(repo, "/"); ok {
+			repo = rest
+		}
+	} else if _, rest, ok := strings.Cut(repo, ":"); ok && strings.Contains(repo, "@") {
+		// scp-like git@github.com:owner/name.git
+		repo = rest
+	}
+	return strings.Trim(strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git"), "/")
+}
+
+// sourceRepo is the repository a component builds from, or "" when it names none
+func (c *component) sourceRepo() string {
+	for _, source := range []string{"github", "gitlab", "bitbucket"} {
+		if repo := scalar(c.field(source, "repo")); repo != "" {
+			return RepoName(repo)
+		}
+	}
+	return RepoName(scalar(c.field("git", "repo_clone_url")))
+}
+
+// resolveDockerfile is the path of the component's Dockerfile under opts.RepoRoot
+func resolveDockerfile(opts Options, dockerfilePath string) string {
+	return filepath.Join(opts.RepoRoot, filepath.FromSlash(strings.TrimPrefix(dockerfilePath, "/")))
+}
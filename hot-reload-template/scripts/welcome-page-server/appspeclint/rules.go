@@ -0,0 +1,290 @@
+package appspeclint
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// buildArgs are the Dockerfile ARGs that pick what the image installs, with
+// their defaults. They only take effect at build time.
+var buildArgs = map[string]string{
+	"INSTALL_NODE":           "false",
+	"NODE_VERSIONS":          "24 22",
+	"NODE_DEFAULT_VERSION":   "24",
+	"INSTALL_PYTHON":         "false",
+	"PYTHON_VERSIONS":        "3.12 3.13",
+	"PYTHON_DEFAULT_VERSION": "3.13",
+	"INSTALL_GOLANG":         "false",
+	"GOLANG_VERSION":         "1.23.4",
+	"INSTALL_RUST":           "false",
+	"INSTALL_RUBY":           "false",
+	"RUBY_VERSIONS":          "3.4.7 3.3.6",
+	"DEFAULT_RUBY":           "3.4.7",
+	"INSTALL_POSTGRES":       "true",
+	"INSTALL_MONGODB":        "false",
+	"INSTALL_MYSQL":          "false",
+	"MONGOSH_VERSION":        "8.0",
+}
+
+// runtimeSettings are read when the container starts, by startup.sh, the sync
+// loop, job-manager.sh and the welcome and health servers. GITHUB_REPO_URL,
+// WORKSPACE_PATH and GITHUB_SYNC_INTERVAL are not listed: the Dockerfile
+// copies those build ARGs into ENV, so they arrive in any scope.
+var runtimeSettings = []string{
+	"GITHUB_TOKEN", "GITHUB_BRANCH", "GITHUB_REPO_FOLDER", "DEV_START_COMMAND",
+	"ENABLE_DEV_HEALTH", "DEV_HEALTH_PORT", "DEV_HEALTH_APP_PORT",
+	"ENABLE_WELCOME_PROXY", "WELCOME_PROXY_APP_PORT", "WELCOME_PAGE_PORT", "ENABLE_LOG_CAPTURE",
+	"PRE_DEPLOY_REPO_URL", "PRE_DEPLOY_FOLDER", "PRE_DEPLOY_COMMAND", "PRE_DEPLOY_TIMEOUT",
+	"POST_DEPLOY_REPO_URL", "POST_DEPLOY_FOLDER", "POST_DEPLOY_COMMAND", "POST_DEPLOY_TIMEOUT",
+	"WELCOME_CONTROL_TOKEN", "ENABLE_WEB_TERMINAL", "WEB_TERMINAL_RECORD",
+}
+
+// booleanSettings are compared against "true" by the scripts and servers
+var booleanSettings = []string{
+	"INSTALL_NODE", "INSTALL_PYTHON", "INSTALL_GOLANG", "INSTALL_RUST", "INSTALL_RUBY",
+	"INSTALL_POSTGRES", "INSTALL_MONGODB", "INSTALL_MYSQL",
+	"ENABLE_DEV_HEALTH", "ENABLE_WELCOME_PROXY", "ENABLE_LOG_CAPTURE",
+	"ENABLE_WEB_TERMINAL", "WEB_TERMINAL_RECORD",
+}
+
+// secondsSettings are whole numbers of seconds
+var secondsSettings = []string{"GITHUB_SYNC_INTERVAL", "PRE_DEPLOY_TIMEOUT", "POST_DEPLOY_TIMEOUT"}
+
+// portSettings are ports the servers listen on or check
+var portSettings = []string{"DEV_HEALTH_PORT", "DEV_HEALTH_APP_PORT", "WELCOME_PROXY_APP_PORT", "WELCOME_PAGE_PORT"}
+
+// secretSettings should be stored encrypted
+var secretSettings = []string{"GITHUB_TOKEN", "WELCOME_CONTROL_TOKEN"}
+
+// validScopes are the scopes App Platform accepts
+var validScopes = []string{"RUN_TIME", "BUILD_TIME", "RUN_AND_BUILD_TIME"}
+
+// devHealthPaths are the endpoints dev-health-server answers
+var devHealthPaths = []string{"/dev_health", "/dev_ready", "/livez", "/readyz", "/startupz"}
+
+// componentRules are run for every service, worker and job
+var componentRules = []func(*component, Options) []Finding{
+	checkComponentEnvs,
+	checkHealthCheck,
+	checkHTTPPort,
+	checkDockerfile,
+}
+
+// checkComponentEnvs applies the env list rules to a component's own envs
+func checkComponentEnvs(c *component, opts Options) []Finding {
+	return checkEnvList(c.envs, c.Name)
+}
+
+// checkEnvList checks each entry of an envs list: scope, value format and
+// duplicates. component is "" for app-level envs.
+func checkEnvList(envs []envVar, component string) []Finding {
+	var findings []Finding
+	add := func(node *yaml.Node, rule, severity, setting, message, hint string) {
+		findings = append(findings, Finding{
+			Rule: rule, Severity: severity, Line: node.Line, Column: node.Column,
+			Component: component, Setting: setting, Message: message, Hint: hint,
+		})
+	}
+
+	seen := make(map[string]envVar)
+	for _, env := range envs {
+		key := env.Key
+		if first, ok := seen[key]; ok {
+			add(env.entry, "duplicate-env", SeverityWarning, key,
+				fmt.Sprintf("%s is set again; it was first set on line %d, and App Platform keeps only one of them.", key, first.entry.Line),
+				"Remove one of the entries.")
+		} else {
+			seen[key] = env
+		}
+
+		scope := env.effectiveScope()
+		switch {
+		case env.Scope != "" && !slices.Contains(validScopes, env.Scope):
+			add(env.scope, "invalid-scope", SeverityError, key,
+				fmt.Sprintf("%s has scope %q, which App Platform does not accept.", key, env.Scope),
+				"Use RUN_TIME, BUILD_TIME or RUN_AND_BUILD_TIME.")
+		case scope == "RUN_TIME" && buildArgs[key] != "":
+			add(scopeNode(env), "build-arg-scope", SeverityError, key,
+				fmt.Sprintf("%s is RUN_TIME, but the Dockerfile reads it as a build ARG, so the image is built with the default (%s).", key, buildArgs[key]),
+				"Set scope: BUILD_TIME.")
+		case scope == "BUILD_TIME" && slices.Contains(runtimeSettings, key):
+			add(scopeNode(env), "runtime-setting-scope", SeverityError, key,
+				fmt.Sprintf("%s is BUILD_TIME, but it is read when the container starts, where it is never set.", key),
+				"Set scope: RUN_TIME.")
+		}
+
+		if env.value != nil && env.value.Kind == yaml.ScalarNode && env.value.Tag != "!!str" && env.value.Tag != "!!null" {
+			add(env.value, "unquoted-value", SeverityWarning, key,
+				fmt.Sprintf("%s's value %s is a YAML %s, but env values must be strings.", key, env.Value, strings.TrimPrefix(env.value.Tag, "!!")),
+				fmt.Sprintf("Quote it: value: \"%s\".", env.Value))
+		}
+
+		value := strings.TrimSpace(env.Value)
+		if value == "" {
+			continue
+		}
+		switch {
+		case slices.Contains(booleanSettings, key) && value != "true" && value != "false":
+			add(env.value, "not-a-boolean", SeverityWarning, key,
+				fmt.Sprintf("%s is %q; only \"true\" turns it on.", key, value),
+				fmt.Sprintf("Set %s to \"true\" or \"false\".", key))
+		case slices.Contains(secondsSettings, key):
+			if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+				add(env.value, "not-a-number", SeverityError, key,
+					fmt.Sprintf("%s is %q, which is not a positive whole number of seconds.", key, value),
+					fmt.Sprintf("Set %s to a number of seconds, e.g. \"300\", without units.", key))
+			}
+		case slices.Contains(portSettings, key):
+			if !validPort(value) {
+				add(env.value, "invalid-port", SeverityError, key,
+					fmt.Sprintf("%s is %q, which is not a valid port.", key, value),
+					"Use a port number between 1 and 65535.")
+			}
+		case slices.Contains(secretSettings, key) && env.Type != "SECRET":
+			add(env.entry, "plaintext-secret", SeverityWarning, key,
+				fmt.Sprintf("%s has a value but is not type: SECRET, so it is stored and shown in plain text.", key),
+				"Add type: SECRET to the entry; App Platform then encrypts the value.")
+		}
+	}
+	return findings
+}
+
+// checkHealthCheck compares health_check with how startup.sh runs
+// dev-health-server: on DEV_HEALTH_PORT, only when ENABLE_DEV_HEALTH=true
+func checkHealthCheck(c *component, opts Options) []Finding {
+	healthCheck := c.field("health_check")
+	if healthCheck == nil || !c.usesTemplate() {
+		return nil
+	}
+	var findings []Finding
+	enabled := c.setting("ENABLE_DEV_HEALTH", "true") == "true"
+	healthPort := c.setting("DEV_HEALTH_PORT", "9090")
+	httpPort := scalarOr(c.field("http_port"), "8080")
+
+	portNode := mapValue(healthCheck, "port")
+	checkPort := scalarOr(portNode, httpPort)
+	pathNode := mapValue(healthCheck, "http_path")
+	checkPath := scalar(pathNode)
+	if portNode == nil {
+		portNode = healthCheck
+	}
+
+	switch {
+	case checkPort == healthPort && !enabled:
+		findings = append(findings, c.finding(portNode, "dev-health-disabled", SeverityError, "ENABLE_DEV_HEALTH",
+			fmt.Sprintf("health_check.port is %s, the dev health server's port, but ENABLE_DEV_HEALTH is not true, so nothing answers and deploys fail.", checkPort),
+			"Set ENABLE_DEV_HEALTH to \"true\", or point health_check.port at http_port ("+httpPort+")."))
+	case checkPort == healthPort && checkPath != "" && !slices.Contains(devHealthPaths, checkPath):
+		findings = append(findings, c.finding(pathNode, "dev-health-path", SeverityError, "",
+			fmt.Sprintf("health_check.http_path is %s, which the dev health server on port %s does not serve.", checkPath, checkPort),
+			"Use /dev_health, or /livez for a check that passes before the app is up."))
+	case checkPort == httpPort && enabled:
+		findings = append(findings, c.finding(portNode, "dev-health-unused", SeverityWarning, "ENABLE_DEV_HEALTH",
+			fmt.Sprintf("ENABLE_DEV_HEALTH is true, but the health check goes to http_port %s, so the dev health server is never checked.", httpPort),
+			"Set ENABLE_DEV_HEALTH to \"false\", or point health_check at port "+healthPort+" with http_path /dev_health while the app is still unstable."))
+	case checkPort != httpPort && checkPort != healthPort && !c.exposesPort(checkPort):
+		findings = append(findings, c.finding(portNode, "health-port-unknown", SeverityWarning, "",
+			fmt.Sprintf("health_check.port is %s, but nothing in the container is set up to listen there (http_port is %s, the dev health server uses %s).", checkPort, httpPort, healthPort),
+			"Point health_check.port at http_port, or at "+healthPort+" with ENABLE_DEV_HEALTH=true."))
+	}
+	return findings
+}
+
+// checkHTTPPort checks that http_port reaches the welcome page, which listens
+// on WELCOME_PAGE_PORT until the app starts and, in proxy mode, forwards to
+// the app on WELCOME_PROXY_APP_PORT
+func checkHTTPPort(c *component, opts Options) []Finding {
+	if c.Kind != "services" || !c.usesTemplate() {
+		return nil
+	}
+	portNode := c.field("http_port")
+	httpPort := scalarOr(portNode, "8080")
+	if portNode == nil {
+		portNode = c.field("name")
+	}
+	welcomePort := c.setting("WELCOME_PAGE_PORT", "8080")
+	proxy := c.setting("ENABLE_WELCOME_PROXY", "false") == "true"
+	appPort := c.setting("WELCOME_PROXY_APP_PORT", "3000")
+
+	var findings []Finding
+	switch {
+	case proxy && appPort == welcomePort:
+		env, _ := c.env("WELCOME_PROXY_APP_PORT")
+		findings = append(findings, c.finding(env.value, "proxy-port-conflict", SeverityError, "WELCOME_PROXY_APP_PORT",
+			fmt.Sprintf("WELCOME_PROXY_APP_PORT is %s, the port the welcome proxy itself listens on, so the app cannot start.", appPort),
+			"Use another port, e.g. \"3000\"."))
+	case httpPort == welcomePort:
+	case proxy && httpPort == appPort:
+		findings = append(findings, c.finding(portNode, "http-port-bypasses-proxy", SeverityWarning, "",
+			fmt.Sprintf("http_port is %s, the app's port, so traffic skips the welcome proxy and the URL shows an error whenever the app is down.", httpPort),
+			"Set http_port to "+welcomePort+" (WELCOME_PAGE_PORT)."))
+	case proxy:
+		findings = append(findings, c.finding(portNode, "http-port-mismatch", SeverityError, "",
+			fmt.Sprintf("http_port is %s, but in proxy mode the welcome proxy listens on %s and the app on %s, so nothing answers.", httpPort, welcomePort, appPort),
+			"Set http_port to "+welcomePort+" (WELCOME_PAGE_PORT)."))
+	default:
+		findings = append(findings, c.finding(portNode, "http-port-mismatch", SeverityWarning, "",
+			fmt.Sprintf("http_port is %s, but the welcome page listens on %s, so the URL shows nothing until your app starts on $PORT.", httpPort, welcomePort),
+			"Set http_port to "+welcomePort+", or set WELCOME_PAGE_PORT to "+httpPort+"."))
+	}
+	return findings
+}
+
+// checkDockerfile checks that dockerfile_path exists in the repository the
+// component builds from, when that is the checkout in opts.RepoRoot
+func checkDockerfile(c *component, opts Options) []Finding {
+	pathNode := c.field("dockerfile_path")
+	dockerfilePath := scalar(pathNode)
+	if opts.RepoRoot == "" || dockerfilePath == "" {
+		return nil
+	}
+	if repo := c.sourceRepo(); repo != "" && repo != RepoName(opts.Repo) {
+		return nil
+	}
+	if fileExists(resolveDockerfile(opts, dockerfilePath)) {
+		return nil
+	}
+	return []Finding{c.finding(pathNode, "dockerfile-missing", SeverityError, "",
+		fmt.Sprintf("dockerfile_path %s does not exist in the repository, so the build fails.", dockerfilePath),
+		"Give the Dockerfile's path from the repository root, e.g. hot-reload-template/Dockerfile.")}
+}
+
+// exposesPort reports whether a port is one of the component's internal_ports
+func (c *component) exposesPort(port string) bool {
+	ports := c.field("internal_ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, node := range ports.Content {
+		if scalar(node) == port {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeNode is where to report a scope problem: the scope, else the entry
+func scopeNode(env envVar) *yaml.Node {
+	if env.scope != nil {
+		return env.scope
+	}
+	return env.entry
+}
+
+// scalarOr returns a scalar node's value, or def when it is missing or empty
+func scalarOr(node *yaml.Node, def string) string {
+	if value := scalar(node); value != "" {
+		return value
+	}
+	return def
+}
+
+// validPort reports whether value is a TCP port number
+func validPort(value string) bool {
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 1 && n <= 65535
+}
@@ -0,0 +1,26 @@
+#!/bin/sh
+#
+# Copies the template's Markdown documentation into bundled-docs/, which the
+# welcome page server embeds at build time and serves under /docs/.
+#
+# Usage: bundle-docs.sh <hot-reload-template directory>
+# Run by "go generate" from this directory and by the Dockerfile.
+#
+set -eu
+
+SRC="${1:?usage: bundle-docs.sh <hot-reload-template directory>}"
+DEST="$(cd "$(dirname "$0")" && pwd)/bundled-docs"
+
+# Start from an empty bundle so removed documents do not linger
+find "$DEST" -name '*.md' -type f -delete 2>/dev/null || true
+mkdir -p "$DEST"
+
+cd "$SRC"
+count=0
+for doc in README.md CUSTOMIZATION.md agent.md docs/*.md app-examples/README.md app-examples/*/README.md; do
+    [ -f "$doc" ] || continue
+    mkdir -p "$DEST/$(dirname "$doc")"
+    cp "$doc" "$DEST/$doc"
+    count=$((count + 1))
+done
+echo "Bundled $count documents into $DEST"
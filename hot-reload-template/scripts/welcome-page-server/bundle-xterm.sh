@@ -0,0 +1,40 @@
+#!/bin/sh
+#
+# Downloads the pinned xterm.js release and its fit addon into
+# terminal-assets/, which the welcome page server embeds at build time and
+# serves under /terminal/assets/, so the web terminal loads no script from a
+# third-party host.
+#
+# Usage: bundle-xterm.sh
+# Run by "go generate" from this directory and by the Dockerfile.
+#
+set -eu
+
+XTERM_VERSION="5.5.0"
+FIT_VERSION="0.10.0"
+# NPM_REGISTRY points at a mirror where registry.npmjs.org is blocked
+REGISTRY="${NPM_REGISTRY:-https://registry.npmjs.org}"
+DEST="$(cd "$(dirname "$0")" && pwd)/terminal-assets"
+
+# fetch <url>: writes the response body to stdout, failing on HTTP errors
+fetch() {
+    if command -v curl >/dev/null 2>&1; then
+        curl -fsSL "$1"
+    else
+        wget -qO- "$1"
+    fi
+}
+
+# extract <package> <version> <file in package> <destination name>
+extract() {
+    tarball="$REGISTRY/$1/-/$(basename "$1")-$2.tgz"
+    fetch "$tarball" | tar -xzOf - "package/$3" > "$DEST/$4.tmp"
+    [ -s "$DEST/$4.tmp" ] || { echo "bundle-xterm.sh: $3 missing from $tarball" >&2; exit 1; }
+    mv "$DEST/$4.tmp" "$DEST/$4"
+}
+
+mkdir -p "$DEST"
+extract @xterm/xterm "$XTERM_VERSION" lib/xterm.js xterm.js
+extract @xterm/xterm "$XTERM_VERSION" css/xterm.css xterm.css
+extract @xterm/addon-fit "$FIT_VERSION" lib/addon-fit.js addon-fit.js
+echo "Bundled xterm.js $XTERM_VERSION and addon-fit $FIT_VERSION into $DEST"
@@ -0,0 +1,136 @@
+// Command appspec-lint checks App Platform app specs for the mistakes that
+// break hot-reload-template deploys.
+//
+// Usage:
+//
+//	appspec-lint [-root dir] [-repo owner/name] [-json] [spec.yaml ...]
+//
+// With no files it lints the first of .do/app.yaml, .do/deploy.template.yaml,
+// appspec.yaml and app.yaml in the current directory. Findings are printed as
+// file:line:column, and the exit status is 1 when any of them is an error.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"welcome-page-server/appspeclint"
+)
+
+// fileReport is one spec's findings in -json output
+type fileReport struct {
+	File     string                `json:"file"`
+	Findings []appspeclint.Finding `json:"findings"`
+}
+
+func main() {
+	root := flag.String("root", "", "repository checkout for checking dockerfile_path (default: the git repository containing each spec)")
+	repo := flag.String("repo", "", "the -root repository as owner/name (default: its origin remote)")
+	asJSON := flag.Bool("json", false, "print findings as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: appspec-lint [flags] [spec.yaml ...]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		for _, name := range appspeclint.SpecFiles {
+			if _, err := os.Stat(name); err == nil {
+				files = []string{name}
+				break
+			}
+		}
+		if len(files) == 0 {
+			fmt.Fprintf(os.Stderr, "appspec-lint: no app spec found (looked for %s)\n", strings.Join(appspeclint.SpecFiles, ", "))
+			os.Exit(2)
+		}
+	}
+
+	var reports []fileReport
+	failed := false
+	for _, file := range files {
+		opts := appspeclint.Options{RepoRoot: *root, Repo: *repo}
+		if opts.RepoRoot == "" {
+			opts.RepoRoot = gitRoot(filepath.Dir(file))
+		}
+		if opts.Repo == "" && opts.RepoRoot != "" {
+			opts.Repo = originRepo(opts.RepoRoot)
+		}
+
+		findings, err := appspeclint.LintFile(file, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "appspec-lint: %v\n", err)
+			os.Exit(2)
+		}
+		for _, finding := range findings {
+			if finding.Severity == appspeclint.SeverityError {
+				failed = true
+			}
+		}
+		if *asJSON {
+			if findings == nil {
+				findings = []appspeclint.Finding{}
+			}
+			reports = append(reports, fileReport{File: file, Findings: findings})
+			continue
+		}
+		printFindings(file, findings)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "appspec-lint: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printFindings prints one spec's findings in the file:line:column format
+// editors and CI annotate
+func printFindings(file string, findings []appspeclint.Finding) {
+	if len(findings) == 0 {
+		fmt.Printf("%s: no problems found\n", file)
+		return
+	}
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+		position := fmt.Sprintf("%s:%d", file, finding.Line)
+		if finding.Column > 0 {
+			position += fmt.Sprintf(":%d", finding.Column)
+		}
+		fmt.Printf("%s: %s: %s [%s]\n", position, finding.Severity, finding.Message, finding.Rule)
+		fmt.Printf("    %s\n", finding.Hint)
+	}
+	fmt.Printf("%s: %d error(s), %d warning(s), %d info\n", file,
+		counts[appspeclint.SeverityError], counts[appspeclint.SeverityWarning], counts[appspeclint.SeverityInfo])
+}
+
+// gitRoot returns the top of the git checkout containing dir, or ""
+func gitRoot(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// originRepo returns the checkout's origin remote as owner/name, or ""
+func originRepo(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return appspeclint.RepoName(string(out))
+}
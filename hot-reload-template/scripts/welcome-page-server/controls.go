@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// syncPIDFile is where startup.sh records the sync loop's PID
+const syncPIDFile = "/tmp/dev-pids/github-sync.pid"
+
+// jobManagerPath is the job runner installed by the Dockerfile
+const jobManagerPath = "/usr/local/bin/job-manager.sh"
+
+// jobLockDir mirrors the lock execute_deploy_jobs takes in github-sync.sh, so
+// a manual run and the sync loop never run jobs at the same time
+const jobLockDir = "/tmp/job-execution.lock"
+
+// controlJobs are the jobs that can be rerun from the page
+var controlJobs = []string{"PRE_DEPLOY", "POST_DEPLOY"}
+
+// ControlResponse is the JSON response for /api/control/sync
+type ControlResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// controlsEnabled reports whether WELCOME_CONTROL_TOKEN is set
+func controlsEnabled() bool {
+	return os.Getenv("WELCOME_CONTROL_TOKEN") != ""
+}
+
+// authorizeControl accepts POST requests carrying "Authorization: Bearer
+// <WELCOME_CONTROL_TOKEN>". Controls are off when no token is configured.
+func authorizeControl(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return checkControlToken(w, r)
+}
+
+// checkControlToken requires "Authorization: Bearer <WELCOME_CONTROL_TOKEN>"
+// on any method, answering 403 or 401 when it is missing or wrong
+func checkControlToken(w http.ResponseWriter, r *http.Request) bool {
+	token := os.Getenv("WELCOME_CONTROL_TOKEN")
+	if token == "" {
+		http.Error(w, "Controls are disabled; set WELCOME_CONTROL_TOKEN to enable them", http.StatusForbidden)
+		return false
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="dev-controls"`)
+		http.Error(w, "Invalid or missing control token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// syncNowHandler wakes the sync loop so it fetches immediately
+func syncNowHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/control/sync endpoint
+	if r.URL.Path != "/api/control/sync" {
+		http.NotFound(w, r)
+		return
+	}
+	if !authorizeControl(w, r) {
+		return
+	}
+
+	pid, err := readPIDFile(syncPIDFile)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Sync loop is not running: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	if err := syscall.Kill(pid, syscall.SIGUSR1); err != nil {
+		http.Error(w, fmt.Sprintf("Could not signal the sync loop (PID %d): %v", pid, err), http.StatusServiceUnavailable)
+		return
+	}
+	log.Printf("Sync requested from %s", r.RemoteAddr)
+	activity.Publish(ActivityEvent{Type: "sync_requested", Level: levelInfo, Message: "Sync requested from the welcome page"})
+
+	writeControlJSON(w, http.StatusAccepted, ControlResponse{Status: "requested", Message: "The sync loop is fetching now; watch the live activity feed for the result."})
+}
+
+// jobRunHandler force-runs a job through job-manager.sh and streams its output
+func jobRunHandler(w http.ResponseWriter, r *http.Request) {
+	job := strings.TrimPrefix(r.URL.Path, "/api/control/jobs/")
+	if !slices.Contains(controlJobs, job) {
+		http.NotFound(w, r)
+		return
+	}
+	if !authorizeControl(w, r) {
+		return
+	}
+	if os.Getenv(job+"_COMMAND") == "" {
+		http.Error(w, fmt.Sprintf("%s_COMMAND is not set, so there is no %s job to run", job, job), http.StatusBadRequest)
+		return
+	}
+
+	if err := os.Mkdir(jobLockDir, 0o755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			http.Error(w, "Deploy jobs are already running; try again when they finish", http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Could not take the job lock: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer os.RemoveAll(jobLockDir)
+
+	cmd := jobCommand(job)
+	output, err := cmd.StdoutPipe()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		http.Error(w, fmt.Sprintf("Could not start %s: %v", jobManagerPath, err), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("%s run requested from %s", job, r.RemoteAddr)
+
+	// Jobs can run for minutes; the server's write timeout would cut them off
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Error disabling write deadline for %s run: %v", job, err)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// The job keeps running if the browser goes away; a half-run migration is
+	// worse than output nobody reads, and the log viewer still has it
+	clientGone := false
+	write := func(text string) {
+		if clientGone {
+			return
+		}
+		if _, err := io.WriteString(w, text); err != nil || rc.Flush() != nil {
+			clientGone = true
+		}
+	}
+
+	write(fmt.Sprintf("$ job-manager.sh execute %s\n", job))
+	reader := bufio.NewReader(output)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			write(stripANSI(redactText(line)))
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	exitCode := 0
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			write(fmt.Sprintf("\n%s could not run: %v\n", job, err))
+			return
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	log.Printf("%s run finished with exit code %d", job, exitCode)
+	write(fmt.Sprintf("\n==> %s finished with exit code %d\n", job, exitCode))
+}
+
+// jobCommand runs job-manager.sh, passing its output through dev-log-capture
+// (when installed and enabled) so manual runs also appear in the log viewer
+func jobCommand(job string) *exec.Cmd {
+	script := `"$1" execute "$2" 2>&1`
+	if getEnvOrDefault("ENABLE_LOG_CAPTURE", "true") == "true" {
+		if _, err := exec.LookPath("dev-log-capture"); err == nil {
+			script = `set -o pipefail; "$1" execute "$2" 2>&1 | dev-log-capture jobs`
+		}
+	}
+	return exec.Command("bash", "-c", script, "job-manager", jobManagerPath, job)
+}
+
+// readPIDFile reads a PID written by startup.sh's record_pid
+func readPIDFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid PID in %s", path)
+	}
+	return pid, nil
+}
+
+// writeControlJSON writes a control endpoint response
+func writeControlJSON(w http.ResponseWriter, statusCode int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
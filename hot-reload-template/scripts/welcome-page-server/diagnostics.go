@@ -0,0 +1,593 @@
+package main
+
+import (
+	"bufio"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Finding severities, most severe first
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// severityRank orders findings so errors are listed first
+var severityRank = map[string]int{severityError: 0, severityWarning: 1, severityInfo: 2}
+
+// monorepoCacheDir mirrors MONOREPO_CACHE in github-sync.sh and job-manager.sh
+const monorepoCacheDir = "/tmp/monorepo-cache"
+
+// jobReposDir mirrors JOB_REPOS_DIR in job-manager.sh
+const jobReposDir = "/tmp/job-repos"
+
+// Finding is one configuration problem with a suggested fix
+type Finding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Setting  string `json:"setting,omitempty"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint"`
+}
+
+// DiagnosticsReport is the JSON response for the /api/diagnostics endpoint
+type DiagnosticsReport struct {
+	Timestamp string         `json:"timestamp"`
+	Summary   map[string]int `json:"summary"`
+	Findings  []Finding      `json:"findings"`
+}
+
+// configSource is the configuration the rules inspect
+type configSource struct {
+	env       map[string]string
+	workspace string
+}
+
+// get returns an environment variable, or "" when unset
+func (c configSource) get(key string) string {
+	return c.env[key]
+}
+
+// currentConfig snapshots the process environment
+func currentConfig() configSource {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		env[key] = value
+	}
+	return configSource{env: env, workspace: getEnvOrDefault("WORKSPACE_PATH", "/workspaces/app")}
+}
+
+// diagnosticRules are run in order; each returns zero or more findings
+var diagnosticRules = []func(configSource) []Finding{
+	checkRepoSettings,
+	checkNumericSettings,
+	checkJobSettings,
+	checkHealthSettings,
+	checkProxySettings,
+	checkControlSettings,
+	checkStartCommand,
+}
+
+// runDiagnostics applies every rule and sorts the findings by severity
+func runDiagnostics(src configSource) DiagnosticsReport {
+	report := DiagnosticsReport{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Summary:   map[string]int{severityError: 0, severityWarning: 0, severityInfo: 0},
+		Findings:  []Finding{},
+	}
+	for _, rule := range diagnosticRules {
+		report.Findings = append(report.Findings, rule(src)...)
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return severityRank[report.Findings[i].Severity] < severityRank[report.Findings[j].Severity]
+	})
+	for i, finding := range report.Findings {
+		// Messages may quote configured values, so mask credentials in them
+		report.Findings[i].Message = redactText(finding.Message)
+		report.Findings[i].Hint = redactText(finding.Hint)
+		report.Summary[finding.Severity]++
+	}
+	return report
+}
+
+// checkRepoSettings validates GITHUB_REPO_URL and the settings that depend on it
+func checkRepoSettings(src configSource) []Finding {
+	var findings []Finding
+	repoURL := src.get("GITHUB_REPO_URL")
+	folder := src.get("GITHUB_REPO_FOLDER")
+
+	if repoURL == "" {
+		if folder != "" {
+			findings = append(findings, Finding{
+				ID:       "repo-folder-without-url",
+				Severity: severityError,
+				Setting:  "GITHUB_REPO_FOLDER",
+				Message:  "GITHUB_REPO_FOLDER is set but GITHUB_REPO_URL is not, so nothing is synced.",
+				Hint:     "Set GITHUB_REPO_URL to the repository that contains " + folder + ".",
+			})
+		}
+		if src.get("GITHUB_BRANCH") != "" {
+			findings = append(findings, Finding{
+				ID:       "branch-without-url",
+				Severity: severityWarning,
+				Setting:  "GITHUB_BRANCH",
+				Message:  "GITHUB_BRANCH is set but GITHUB_REPO_URL is not.",
+				Hint:     "Set GITHUB_REPO_URL, or remove GITHUB_BRANCH.",
+			})
+		}
+		return findings
+	}
+
+	u, err := url.Parse(repoURL)
+	isSSH := strings.HasPrefix(repoURL, "git@")
+	switch {
+	case isSSH:
+		findings = append(findings, Finding{
+			ID:       "repo-url-ssh",
+			Severity: severityError,
+			Setting:  "GITHUB_REPO_URL",
+			Message:  "GITHUB_REPO_URL uses SSH; the container has no SSH key to clone with.",
+			Hint:     "Use the HTTPS URL (https://github.com/owner/repo.git) and set GITHUB_TOKEN for private repositories.",
+		})
+	case err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "":
+		findings = append(findings, Finding{
+			ID:       "repo-url-invalid",
+			Severity: severityError,
+			Setting:  "GITHUB_REPO_URL",
+			Message:  "GITHUB_REPO_URL is not a valid HTTPS repository URL.",
+			Hint:     "Use the form https://github.com/owner/repo.git.",
+		})
+	case u.User != nil:
+		findings = append(findings, Finding{
+			ID:       "repo-url-credentials",
+			Severity: severityWarning,
+			Setting:  "GITHUB_REPO_URL",
+			Message:  "GITHUB_REPO_URL contains embedded credentials, which can leak through logs and process listings.",
+			Hint:     "Remove the credentials from the URL and put the token in GITHUB_TOKEN (type SECRET).",
+		})
+	}
+
+	if folder != "" && (filepath.IsAbs(folder) || strings.Contains(folder, "..")) {
+		findings = append(findings, Finding{
+			ID:       "repo-folder-invalid",
+			Severity: severityError,
+			Setting:  "GITHUB_REPO_FOLDER",
+			Message:  fmt.Sprintf("GITHUB_REPO_FOLDER %q must be a path relative to the repository root.", folder),
+			Hint:     "Use a relative path such as apps/web, without a leading / or ..",
+		})
+	} else if folder != "" {
+		if cache := monorepoCachePath(repoURL); dirExists(cache) && !dirExists(filepath.Join(cache, folder)) {
+			findings = append(findings, Finding{
+				ID:       "repo-folder-missing",
+				Severity: severityError,
+				Setting:  "GITHUB_REPO_FOLDER",
+				Message:  fmt.Sprintf("Folder %q does not exist in the cloned repository.", folder),
+				Hint:     "Check the spelling and case of GITHUB_REPO_FOLDER against the repository layout.",
+			})
+		}
+	}
+
+	if !dirExists(filepath.Join(repoCheckoutPath(src), ".git")) {
+		findings = append(findings, Finding{
+			ID:       "repo-not-cloned",
+			Severity: severityWarning,
+			Setting:  "GITHUB_REPO_URL",
+			Message:  "The repository has not been cloned (yet).",
+			Hint:     "Check the container logs for clone errors. Private repositories need GITHUB_TOKEN, and GITHUB_BRANCH must exist.",
+		})
+	}
+	return findings
+}
+
+// secondsSettings are the template's settings that startup.sh or the dev
+// health server read as a whole number of seconds. Other *_TIMEOUT variables
+// belong to the app.
+var secondsSettings = map[string]bool{
+	"GITHUB_SYNC_INTERVAL":     true,
+	"PRE_DEPLOY_TIMEOUT":       true,
+	"POST_DEPLOY_TIMEOUT":      true,
+	"DEV_HEALTH_READY_TIMEOUT": true,
+}
+
+// checkNumericSettings flags intervals, timeouts and ports that are not numbers
+func checkNumericSettings(src configSource) []Finding {
+	var findings []Finding
+	keys := make([]string, 0, len(src.env))
+	for key := range src.env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.TrimSpace(src.env[key])
+		if value == "" {
+			continue
+		}
+		switch {
+		case secondsSettings[key]:
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				findings = append(findings, Finding{
+					ID:       "not-a-number",
+					Severity: severityError,
+					Setting:  key,
+					Message:  fmt.Sprintf("%s is %q, which is not a positive whole number of seconds.", key, value),
+					Hint:     fmt.Sprintf("Set %s to a number of seconds, e.g. \"300\", without units.", key),
+				})
+			} else if key == "GITHUB_SYNC_INTERVAL" && n < 5 {
+				findings = append(findings, Finding{
+					ID:       "sync-interval-short",
+					Severity: severityWarning,
+					Setting:  key,
+					Message:  fmt.Sprintf("GITHUB_SYNC_INTERVAL is %ds; fetching this often can hit GitHub rate limits.", n),
+					Hint:     "Use 10 seconds or more.",
+				})
+			}
+		case strings.HasSuffix(key, "_PORT") && (strings.HasPrefix(key, "DEV_HEALTH_") || strings.HasPrefix(key, "WELCOME_")):
+			if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+				findings = append(findings, Finding{
+					ID:       "invalid-port",
+					Severity: severityError,
+					Setting:  key,
+					Message:  fmt.Sprintf("%s is %q, which is not a valid port.", key, value),
+					Hint:     "Use a port number between 1 and 65535.",
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// checkJobSettings validates PRE_DEPLOY and POST_DEPLOY configuration
+func checkJobSettings(src configSource) []Finding {
+	var findings []Finding
+	for _, job := range []string{"PRE_DEPLOY", "POST_DEPLOY"} {
+		command := src.get(job + "_COMMAND")
+		folder := src.get(job + "_FOLDER")
+		jobRepo := src.get(job + "_REPO_URL")
+
+		if command == "" {
+			if folder != "" || jobRepo != "" {
+				findings = append(findings, Finding{
+					ID:       "job-without-command",
+					Severity: severityInfo,
+					Setting:  job + "_COMMAND",
+					Message:  fmt.Sprintf("%s_FOLDER or %s_REPO_URL is set, but %s_COMMAND is empty, so the job never runs.", job, job, job),
+					Hint:     fmt.Sprintf("Set %s_COMMAND (e.g. \"bash migrate.sh\") to enable the job.", job),
+				})
+			}
+			continue
+		}
+
+		dir, known := jobDirectory(src, job)
+		if known && !dirExists(dir) {
+			findings = append(findings, Finding{
+				ID:       "job-folder-missing",
+				Severity: severityError,
+				Setting:  job + "_FOLDER",
+				Message:  fmt.Sprintf("%s runs in %s, which does not exist.", job, dir),
+				Hint:     fmt.Sprintf("Check %s_FOLDER against your repository layout; it is relative to the app folder (or to %s_REPO_URL when set).", job, job),
+			})
+		}
+	}
+	return findings
+}
+
+// jobDirectory resolves a job's working directory the way job-manager.sh does.
+// It returns false when the directory can't be known yet (job repo not cloned).
+func jobDirectory(src configSource, job string) (string, bool) {
+	folder := src.get(job + "_FOLDER")
+	var base string
+	switch {
+	case src.get(job+"_REPO_URL") != "":
+		base = filepath.Join(jobReposDir, repoHash(src.get(job+"_REPO_URL")))
+		if !dirExists(base) {
+			return "", false
+		}
+	case src.get("GITHUB_REPO_FOLDER") != "":
+		cache := monorepoCachePath(src.get("GITHUB_REPO_URL"))
+		if !dirExists(cache) {
+			return "", false
+		}
+		base = filepath.Join(cache, src.get("GITHUB_REPO_FOLDER"))
+	default:
+		base = src.workspace
+		if !dirExists(filepath.Join(base, ".git")) {
+			return "", false
+		}
+	}
+	if folder == "" {
+		return base, true
+	}
+	return filepath.Join(base, folder), true
+}
+
+// devHealthRules are the app spec findings about ENABLE_DEV_HEALTH that the
+// configuration check reports too, since they decide whether deploys pass
+var devHealthRules = map[string]bool{"dev-health-disabled": true, "dev-health-unused": true}
+
+// checkHealthSettings validates ENABLE_DEV_HEALTH and compares it with the
+// health check in the repository's app spec, as the app spec linter reads it
+func checkHealthSettings(src configSource) []Finding {
+	var findings []Finding
+	enabled := src.get("ENABLE_DEV_HEALTH")
+	if enabled != "" && enabled != "true" && enabled != "false" {
+		findings = append(findings, Finding{
+			ID:       "dev-health-invalid",
+			Severity: severityWarning,
+			Setting:  "ENABLE_DEV_HEALTH",
+			Message:  fmt.Sprintf("ENABLE_DEV_HEALTH is %q; only \"true\" enables the health server.", enabled),
+			Hint:     "Set ENABLE_DEV_HEALTH to \"true\" or \"false\".",
+		})
+	}
+
+	report := lintAppSpec(src)
+	for _, finding := range report.Findings {
+		if !devHealthRules[finding.Rule] {
+			continue
+		}
+		findings = append(findings, Finding{
+			ID:       finding.Rule,
+			Severity: finding.Severity,
+			Setting:  finding.Setting,
+			Message:  fmt.Sprintf("%s line %d: %s", report.File, finding.Line, finding.Message),
+			Hint:     finding.Hint,
+		})
+	}
+	return findings
+}
+
+// checkProxySettings validates proxy mode, where the app must move off port 8080
+func checkProxySettings(src configSource) []Finding {
+	enabled := src.get("ENABLE_WELCOME_PROXY")
+	if enabled != "" && enabled != "true" && enabled != "false" {
+		return []Finding{{
+			ID:       "welcome-proxy-invalid",
+			Severity: severityWarning,
+			Setting:  "ENABLE_WELCOME_PROXY",
+			Message:  fmt.Sprintf("ENABLE_WELCOME_PROXY is %q; only \"true\" enables proxy mode.", enabled),
+			Hint:     "Set ENABLE_WELCOME_PROXY to \"true\" or \"false\".",
+		}}
+	}
+	if enabled != "true" {
+		return nil
+	}
+
+	appPort := src.get("WELCOME_PROXY_APP_PORT")
+	if appPort == "" {
+		appPort = "3000"
+	}
+	welcomePort := src.get("WELCOME_PAGE_PORT")
+	if welcomePort == "" {
+		welcomePort = "8080"
+	}
+	if appPort == welcomePort {
+		return []Finding{{
+			ID:       "welcome-proxy-loop",
+			Severity: severityError,
+			Setting:  "WELCOME_PROXY_APP_PORT",
+			Message:  fmt.Sprintf("WELCOME_PROXY_APP_PORT is %s, the port the welcome page server itself listens on.", appPort),
+			Hint:     "Leave WELCOME_PROXY_APP_PORT unset (3000) or pick another free port.",
+		}}
+	}
+	if mentionsPort(src.get("DEV_START_COMMAND"), welcomePort) {
+		return []Finding{{
+			ID:       "welcome-proxy-port-conflict",
+			Severity: severityWarning,
+			Setting:  "DEV_START_COMMAND",
+			Message:  fmt.Sprintf("DEV_START_COMMAND mentions port %s, which the welcome page proxy already uses.", welcomePort),
+			Hint:     fmt.Sprintf("Start your app on $PORT (set to %s in proxy mode) instead of a fixed port.", appPort),
+		}}
+	}
+	// The script usually hard-codes the port, or frees it with fuser/lsof
+	// before a restart, which kills the proxy
+	if script := startScriptPath(src); script != "" {
+		if line := scriptPortLine(script, welcomePort); line > 0 {
+			name, err := filepath.Rel(src.workspace, script)
+			if err != nil || strings.HasPrefix(name, "..") {
+				name = script
+			}
+			return []Finding{{
+				ID:       "welcome-proxy-port-conflict",
+				Severity: severityWarning,
+				Setting:  "DEV_START_COMMAND",
+				Message:  fmt.Sprintf("%s uses port %s on line %d, which the welcome page proxy already uses.", filepath.ToSlash(name), welcomePort, line),
+				Hint:     fmt.Sprintf("Start your app on \"${PORT:-%s}\" (set to %s in proxy mode), and stop it by PID rather than by port.", welcomePort, appPort),
+			}}
+		}
+	}
+	return nil
+}
+
+// mentionsPort reports whether port appears as a whole number in s
+func mentionsPort(s, port string) bool {
+	numbers := strings.FieldsFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	return slices.Contains(numbers, port)
+}
+
+// shellDefaultPattern matches ${VAR:-default} expansions, whose default only
+// applies when the variable is unset
+var shellDefaultPattern = regexp.MustCompile(`\$\{\w+:-[^}]*\}`)
+
+// scriptPortLine returns the first line of a shell script that uses port
+// outside a comment or a ${PORT:-8080}-style default, or 0
+func scriptPortLine(path, port string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if mentionsPort(shellDefaultPattern.ReplaceAllString(line, ""), port) {
+			return n
+		}
+	}
+	return 0
+}
+
+// startScriptPath is the workspace script startup.sh runs: the one named by a
+// "bash script.sh" DEV_START_COMMAND, or dev_startup.sh / startup.sh when the
+// command is unset. It returns "" when there is no such script.
+func startScriptPath(src configSource) string {
+	command := src.get("DEV_START_COMMAND")
+	var candidates []string
+	if command == "" {
+		candidates = []string{"dev_startup.sh", "startup.sh"}
+	} else if fields := strings.Fields(command); len(fields) >= 2 && (fields[0] == "bash" || fields[0] == "sh") && strings.HasSuffix(fields[1], ".sh") {
+		candidates = []string{fields[1]}
+	}
+	for _, script := range candidates {
+		if !filepath.IsAbs(script) {
+			script = filepath.Join(src.workspace, script)
+		}
+		if fileExists(script) {
+			return script
+		}
+	}
+	return ""
+}
+
+// minControlTokenLength is the shortest WELCOME_CONTROL_TOKEN accepted without
+// a warning; the token lets anyone who has it run deploy jobs
+const minControlTokenLength = 16
+
+// checkControlSettings flags control tokens that are easy to guess and a web
+// terminal that cannot be used without one
+func checkControlSettings(src configSource) []Finding {
+	var findings []Finding
+	token := src.get("WELCOME_CONTROL_TOKEN")
+	terminal := src.get("ENABLE_WEB_TERMINAL") == "true"
+
+	if terminal && token == "" {
+		findings = append(findings, Finding{
+			ID:       "web-terminal-no-token",
+			Severity: severityWarning,
+			Setting:  "ENABLE_WEB_TERMINAL",
+			Message:  "ENABLE_WEB_TERMINAL is true but WELCOME_CONTROL_TOKEN is not set, so the web terminal stays disabled.",
+			Hint:     "Set WELCOME_CONTROL_TOKEN as a secret; the terminal page asks for it before opening a shell",
+		})
+	}
+	if token != "" && len(token) < minControlTokenLength {
+		allows := "rerunning deploy jobs"
+		if terminal {
+			allows = "opening a shell in the container"
+		}
+		findings = append(findings, Finding{
+			ID:       "welcome-control-token-short",
+			Severity: severityWarning,
+			Setting:  "WELCOME_CONTROL_TOKEN",
+			Message:  fmt.Sprintf("WELCOME_CONTROL_TOKEN is only %d characters long, and it allows %s from the public welcome page.", len(token), allows),
+			Hint:     fmt.Sprintf("Use a random value of at least %d characters, e.g. the output of: openssl rand -hex 24", minControlTokenLength),
+		})
+	}
+	return findings
+}
+
+// checkStartCommand makes sure there is something to start. It stays quiet
+// until the workspace has content, since a missing clone is reported separately.
+func checkStartCommand(src configSource) []Finding {
+	command := src.get("DEV_START_COMMAND")
+	entries, err := os.ReadDir(src.workspace)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+
+	if command == "" {
+		if fileExists(filepath.Join(src.workspace, "dev_startup.sh")) || fileExists(filepath.Join(src.workspace, "startup.sh")) {
+			return nil
+		}
+		return []Finding{{
+			ID:       "no-start-command",
+			Severity: severityWarning,
+			Setting:  "DEV_START_COMMAND",
+			Message:  "DEV_START_COMMAND is not set and the workspace has no dev_startup.sh, so no app is started.",
+			Hint:     "Add a dev_startup.sh to your repository (see the suggested script below), or set DEV_START_COMMAND.",
+		}}
+	}
+
+	// "bash dev_startup.sh" and friends: make sure the script is there
+	fields := strings.Fields(command)
+	if len(fields) >= 2 && (fields[0] == "bash" || fields[0] == "sh") && strings.HasSuffix(fields[1], ".sh") {
+		script := fields[1]
+		if !filepath.IsAbs(script) {
+			script = filepath.Join(src.workspace, script)
+		}
+		if !fileExists(script) {
+			return []Finding{{
+				ID:       "start-script-missing",
+				Severity: severityError,
+				Setting:  "DEV_START_COMMAND",
+				Message:  fmt.Sprintf("DEV_START_COMMAND runs %s, which is not in the workspace.", fields[1]),
+				Hint:     "Commit the script to your repository (in GITHUB_REPO_FOLDER for monorepos), or fix the path.",
+			}}
+		}
+	}
+	return nil
+}
+
+// repoCheckoutPath is where the main repository is cloned
+func repoCheckoutPath(src configSource) string {
+	if src.get("GITHUB_REPO_FOLDER") != "" {
+		return monorepoCachePath(src.get("GITHUB_REPO_URL"))
+	}
+	return src.workspace
+}
+
+// monorepoCachePath mirrors get_repo_hash in the sync scripts, which hash
+// the URL with the trailing newline added by echo
+func monorepoCachePath(repoURL string) string {
+	return filepath.Join(monorepoCacheDir, repoHash(repoURL))
+}
+
+// repoHash is the md5 of the URL plus newline, as computed by `echo "$url" | md5sum`
+func repoHash(repoURL string) string {
+	sum := md5.Sum([]byte(repoURL + "\n"))
+	return hex.EncodeToString(sum[:])
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// diagnosticsHandler handles requests to the /api/diagnostics endpoint
+func diagnosticsHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/diagnostics endpoint
+	if r.URL.Path != "/api/diagnostics" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(runDiagnostics(currentConfig())); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
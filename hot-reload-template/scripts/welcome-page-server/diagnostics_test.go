@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckNumericSettings(t *testing.T) {
+	src := configSource{env: map[string]string{
+		"GITHUB_SYNC_INTERVAL":     "2",
+		"PRE_DEPLOY_TIMEOUT":       "5m",
+		"POST_DEPLOY_TIMEOUT":      "600",
+		"DEV_HEALTH_READY_TIMEOUT": "2s",
+		"REQUEST_TIMEOUT":          "30s",
+		"DEV_HEALTH_PORT":          "99999",
+	}}
+	got := map[string]string{}
+	for _, finding := range checkNumericSettings(src) {
+		got[finding.Setting] = finding.ID
+	}
+	want := map[string]string{
+		"GITHUB_SYNC_INTERVAL":     "sync-interval-short",
+		"PRE_DEPLOY_TIMEOUT":       "not-a-number",
+		"DEV_HEALTH_READY_TIMEOUT": "not-a-number",
+		"DEV_HEALTH_PORT":          "invalid-port",
+	}
+	if len(got) != len(want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	for setting, id := range want {
+		if got[setting] != id {
+			t.Errorf("%s: finding %q, want %q", setting, got[setting], id)
+		}
+	}
+}
+
+func TestCheckProxySettingsStartScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		script  string
+		want    string
+	}{
+		{"port in command", "npm run dev -- --port 8080", "", "DEV_START_COMMAND mentions port 8080"},
+		{"port in default script", "", "#!/bin/bash\n# listens on 8080 by default\nexec rails server -p 8080\n", "dev_startup.sh uses port 8080 on line 3"},
+		{"kill by port", "bash dev_startup.sh", "/tmp/app &\nfuser -k 8080/tcp\n", "dev_startup.sh uses port 8080 on line 2"},
+		{"port from PORT", "", "# App Platform routes to 8080\nexec uvicorn main:app --port \"${PORT:-8080}\"\n", ""},
+		{"other script", "bash other.sh", "exec rails server -p 8080\n", ""},
+		{"no script", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := t.TempDir()
+			if tt.script != "" {
+				if err := os.WriteFile(filepath.Join(workspace, "dev_startup.sh"), []byte(tt.script), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			src := configSource{workspace: workspace, env: map[string]string{
+				"ENABLE_WELCOME_PROXY": "true",
+				"DEV_START_COMMAND":    tt.command,
+			}}
+			findings := checkProxySettings(src)
+			if tt.want == "" {
+				if len(findings) != 0 {
+					t.Errorf("unexpected findings: %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].ID != "welcome-proxy-port-conflict" || !strings.Contains(findings[0].Message, tt.want) {
+				t.Errorf("findings = %+v, want a port conflict mentioning %q", findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHealthSettingsAppSpec(t *testing.T) {
+	spec := `name: dev
+services:
+  - name: web
+    dockerfile_path: hot-reload-template/Dockerfile
+    http_port: 8080
+    health_check:
+      http_path: /health
+      port: %s
+    envs:
+      - key: ENABLE_DEV_HEALTH
+        value: "%s"
+`
+	tests := []struct {
+		name     string
+		port     string
+		enabled  string
+		id       string
+		severity string
+	}{
+		{"health server unused", "8080", "true", "dev-health-unused", severityWarning},
+		{"health server disabled", "9090", "false", "dev-health-disabled", severityError},
+		{"app checked", "8080", "false", "", ""},
+		{"health server checked", "9090", "true", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := t.TempDir()
+			if err := os.WriteFile(filepath.Join(workspace, "appspec.yaml"), []byte(fmt.Sprintf(spec, tt.port, tt.enabled)), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			findings := checkHealthSettings(configSource{workspace: workspace, env: map[string]string{"ENABLE_DEV_HEALTH": tt.enabled}})
+			if tt.id == "" {
+				if len(findings) != 0 {
+					t.Errorf("unexpected findings: %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].ID != tt.id || findings[0].Severity != tt.severity || !strings.HasPrefix(findings[0].Message, "appspec.yaml line 8: ") {
+				t.Errorf("findings = %+v, want one %s %s on appspec.yaml line 8", findings, tt.severity, tt.id)
+			}
+		})
+	}
+}
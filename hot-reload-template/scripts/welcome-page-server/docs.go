@@ -0,0 +1,663 @@
+package main
+
+import (
+	"embed"
+	"html"
+	"html/template"
+	"io/fs"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:generate sh bundle-docs.sh ../..
+
+// templateRepoURL is the template's GitHub repository, for links to files
+// that are not bundled
+const templateRepoURL = "https://github.com/bikram20/do-app-platform-ai-dev-workflow"
+
+// templateDirURL is hot-reload-template/ in the repository
+const templateDirURL = templateRepoURL + "/blob/main/hot-reload-template/"
+
+// maxDocResults caps the search results page
+const maxDocResults = 30
+
+// bundledDocs holds the template's Markdown documentation, copied into
+// bundled-docs/ by bundle-docs.sh before the build so the pages match the
+// container version and work without GitHub
+//
+//go:embed all:bundled-docs
+var bundledDocs embed.FS
+
+// docOrder puts the main guides first in the navigation; other documents
+// follow in path order
+var docOrder = []string{"README.md", "agent.md", "CUSTOMIZATION.md", "docs/JOBS.md", "app-examples/README.md"}
+
+// doc is one rendered Markdown file
+type doc struct {
+	Path        string
+	Title       string
+	Description string
+	HTML        template.HTML
+	Headings    []mdHeading
+	Sections    []mdSection
+}
+
+// docGroup is a navigation section
+type docGroup struct {
+	Name string
+	Docs []*doc
+}
+
+// docSet is every bundled document, rendered once
+type docSet struct {
+	docs   []*doc
+	byPath map[string]*doc
+	groups []docGroup
+}
+
+var (
+	docsOnce   sync.Once
+	loadedDocs *docSet
+)
+
+// loadDocs renders the bundled documentation on first use
+func loadDocs() *docSet {
+	docsOnce.Do(func() {
+		loadedDocs = buildDocSet(bundledDocs)
+	})
+	return loadedDocs
+}
+
+// buildDocSet reads and renders every .md file under bundled-docs
+func buildDocSet(files embed.FS) *docSet {
+	set := &docSet{byPath: make(map[string]*doc)}
+	root, err := fs.Sub(files, "bundled-docs")
+	if err != nil {
+		log.Printf("Error reading bundled docs: %v", err)
+		return set
+	}
+
+	sources := make(map[string]string)
+	err = fs.WalkDir(root, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != ".md" {
+			return err
+		}
+		data, err := fs.ReadFile(root, name)
+		if err != nil {
+			return err
+		}
+		sources[name] = string(data)
+		set.byPath[name] = &doc{Path: name}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error reading bundled docs: %v", err)
+	}
+
+	// Links are resolved against the full set, so render after collecting paths
+	for name, source := range sources {
+		d := set.byPath[name]
+		rendered, headings, sections := renderMarkdown(source, set.linkResolver(name))
+		d.HTML = template.HTML(rendered)
+		d.Headings = headings
+		d.Sections = sections
+		d.Title = name
+		for _, heading := range headings {
+			if heading.Level == 1 {
+				d.Title = heading.Text
+				break
+			}
+		}
+		d.Description = docDescription(sections)
+		set.docs = append(set.docs, d)
+	}
+
+	rank := func(name string) int {
+		for i, ordered := range docOrder {
+			if ordered == name {
+				return i
+			}
+		}
+		return len(docOrder)
+	}
+	sort.Slice(set.docs, func(i, j int) bool {
+		a, b := set.docs[i].Path, set.docs[j].Path
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		return a < b
+	})
+
+	guides := docGroup{Name: "Guides"}
+	examples := docGroup{Name: "App examples"}
+	for _, d := range set.docs {
+		if strings.HasPrefix(d.Path, "app-examples/") {
+			examples.Docs = append(examples.Docs, d)
+		} else {
+			guides.Docs = append(guides.Docs, d)
+		}
+	}
+	for _, group := range []docGroup{guides, examples} {
+		if len(group.Docs) > 0 {
+			set.groups = append(set.groups, group)
+		}
+	}
+	return set
+}
+
+// docDescription is the start of a document's first text, for the index
+func docDescription(sections []mdSection) string {
+	for _, section := range sections {
+		if section.Text != "" {
+			return truncateText(section.Text, 200)
+		}
+	}
+	return ""
+}
+
+// truncateText shortens text to about n bytes at a word boundary
+func truncateText(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	cut := strings.LastIndex(text[:n], " ")
+	if cut < n/2 {
+		cut = n
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimSpace(text[:cut]) + "…"
+}
+
+// linkResolver rewrites relative links in the document at from. The docs are
+// served at the same paths they have in hot-reload-template/, so links between
+// bundled documents work unchanged; links to directories go to their README,
+// and links to anything else go to GitHub.
+func (s *docSet) linkResolver(from string) func(string) string {
+	return func(dest string) string {
+		target, fragment, hasFragment := strings.Cut(dest, "#")
+		if target == "" || strings.HasPrefix(target, "/") {
+			return dest
+		}
+		if hasFragment {
+			fragment = "#" + fragment
+		}
+
+		resolved := path.Join(path.Dir(from), target)
+		if resolved == ".." || strings.HasPrefix(resolved, "../") {
+			// Outside hot-reload-template/, e.g. the repository's root README
+			resolved = path.Join("hot-reload-template", resolved)
+			if resolved == ".." || strings.HasPrefix(resolved, "../") {
+				return templateRepoURL
+			}
+			return templateRepoURL + "/blob/main/" + resolved + fragment
+		}
+		if _, ok := s.byPath[resolved]; ok {
+			return dest
+		}
+		if _, ok := s.byPath[path.Join(resolved, "README.md")]; ok {
+			return strings.TrimSuffix(target, "/") + "/README.md" + fragment
+		}
+		return templateDirURL + resolved + fragment
+	}
+}
+
+// DocSearchResult is one matching section
+type DocSearchResult struct {
+	Path    string
+	Title   string
+	Heading string
+	Anchor  string
+	Snippet template.HTML
+	score   int
+}
+
+// search finds the sections containing every word of query
+func (s *docSet) search(query string) []DocSearchResult {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	patterns := make([]*regexp.Regexp, len(terms))
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+		patterns[i] = regexp.MustCompile("(?i)" + quoted[i])
+	}
+	highlight := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+
+	var results []DocSearchResult
+	for _, d := range s.docs {
+		for _, section := range d.Sections {
+			score := 0
+			for _, pattern := range patterns {
+				inText := len(pattern.FindAllStringIndex(section.Text, 20))
+				inHeading := pattern.MatchString(section.Heading.Text)
+				if inText == 0 && !inHeading && !pattern.MatchString(d.Title) {
+					score = 0
+					break
+				}
+				score += inText
+				if inHeading {
+					score += 10
+				}
+			}
+			if score == 0 {
+				continue
+			}
+			result := DocSearchResult{
+				Path:    d.Path,
+				Title:   d.Title,
+				Heading: section.Heading.Text,
+				Anchor:  section.Heading.ID,
+				Snippet: searchSnippet(section.Text, patterns[0], highlight),
+				score:   score,
+			}
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	if len(results) > maxDocResults {
+		results = results[:maxDocResults]
+	}
+	return results
+}
+
+// searchSnippet returns about 200 bytes of text around the first match, with
+// every search term highlighted
+func searchSnippet(text string, first, highlight *regexp.Regexp) template.HTML {
+	start := 0
+	if loc := first.FindStringIndex(text); loc != nil && loc[0] > 60 {
+		start = loc[0] - 60
+		for start < len(text) && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		if space := strings.IndexByte(text[start:], ' '); space >= 0 && space < 20 {
+			start += space + 1
+		}
+	}
+	snippet := truncateText(text[start:], 200)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range highlight.FindAllStringIndex(snippet, -1) {
+		out.WriteString(html.EscapeString(snippet[last:loc[0]]))
+		out.WriteString("<mark>" + html.EscapeString(snippet[loc[0]:loc[1]]) + "</mark>")
+		last = loc[1]
+	}
+	out.WriteString(html.EscapeString(snippet[last:]))
+	return template.HTML(out.String())
+}
+
+// DocsPageData holds data for the docs template
+type DocsPageData struct {
+	Root    string
+	Groups  []docGroup
+	Current string
+	Doc     *doc
+	TOC     []mdHeading
+	Query   string
+	Results []DocSearchResult
+	Search  bool
+	Bundled bool
+}
+
+// docsRedirectHandler sends /docs to /docs/. The redirect is relative so it
+// also works under /_dev in proxy mode, where ServeMux's own would not.
+func docsRedirectHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Location", "docs/")
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+// docsHandler serves the index at /docs/, search at /docs/search and each
+// document at /docs/<path>.md
+func docsHandler(w http.ResponseWriter, r *http.Request) {
+	set := loadDocs()
+	name := strings.TrimPrefix(r.URL.Path, "/docs/")
+
+	// Pages link to each other relative to /docs/, wherever that is mounted
+	root := "./"
+	if depth := strings.Count(name, "/"); depth > 0 {
+		root = strings.Repeat("../", depth)
+	}
+	data := DocsPageData{Root: root, Groups: set.groups, Current: name, Bundled: len(set.docs) > 0}
+
+	switch {
+	case name == "":
+	case name == "search":
+		data.Search = true
+		data.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+		data.Results = set.search(data.Query)
+	default:
+		d, ok := set.byPath[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data.Doc = d
+		for _, heading := range d.Headings {
+			if heading.Level == 2 || heading.Level == 3 {
+				data.TOC = append(data.TOC, heading)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if err := docsPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Error executing template: %v", err)
+	}
+}
+
+// docsPageTemplate renders the docs index, search results and documents
+var docsPageTemplate = template.Must(template.New("docs").Parse(docsPageHTML))
+
+// docsPageHTML is the HTML template for the documentation pages
+const docsPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{if .Doc}}{{.Doc.Title}} · {{else if .Search}}Search · {{end}}Documentation</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            color: #24292f;
+            background: #f6f8fa;
+            line-height: 1.6;
+            display: flex;
+            min-height: 100vh;
+        }
+        a {
+            color: #0969da;
+            text-decoration: none;
+        }
+        a:hover {
+            text-decoration: underline;
+        }
+        nav {
+            width: 270px;
+            flex-shrink: 0;
+            background: linear-gradient(180deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 20px;
+            position: sticky;
+            top: 0;
+            height: 100vh;
+            overflow-y: auto;
+        }
+        nav a {
+            color: white;
+        }
+        nav .brand {
+            font-weight: 600;
+            font-size: 1.1em;
+            display: block;
+            margin-bottom: 15px;
+        }
+        nav form input {
+            width: 100%;
+            padding: 6px 10px;
+            border: none;
+            border-radius: 4px;
+            margin-bottom: 15px;
+        }
+        nav h2 {
+            font-size: 0.8em;
+            text-transform: uppercase;
+            letter-spacing: 0.05em;
+            opacity: 0.8;
+            margin: 15px 0 5px;
+        }
+        nav ul {
+            list-style: none;
+        }
+        nav li a {
+            display: block;
+            padding: 3px 8px;
+            border-radius: 4px;
+            font-size: 0.92em;
+        }
+        nav li a.current {
+            background: rgba(255, 255, 255, 0.2);
+        }
+        nav .back {
+            display: block;
+            margin-top: 20px;
+            font-size: 0.85em;
+            opacity: 0.9;
+        }
+        main {
+            flex: 1;
+            min-width: 0;
+            padding: 30px 40px;
+        }
+        article {
+            background: white;
+            border: 1px solid #d0d7de;
+            border-radius: 8px;
+            padding: 30px 40px;
+            max-width: 960px;
+        }
+        .source {
+            color: #57606a;
+            font-size: 0.85em;
+            margin-bottom: 15px;
+        }
+        details.toc {
+            background: #f6f8fa;
+            border-radius: 6px;
+            padding: 8px 15px;
+            margin-bottom: 20px;
+            font-size: 0.92em;
+        }
+        details.toc summary {
+            cursor: pointer;
+            font-weight: 600;
+        }
+        details.toc ul {
+            list-style: none;
+            margin-top: 5px;
+        }
+        details.toc .level-3 {
+            padding-left: 18px;
+        }
+        article h1, article h2, article h3, article h4, article h5, article h6 {
+            margin: 24px 0 12px;
+            line-height: 1.25;
+            position: relative;
+        }
+        article h1 {
+            font-size: 2em;
+            border-bottom: 1px solid #d8dee4;
+            padding-bottom: 8px;
+        }
+        article h2 {
+            font-size: 1.5em;
+            border-bottom: 1px solid #d8dee4;
+            padding-bottom: 6px;
+        }
+        article h3 {
+            font-size: 1.25em;
+        }
+        article .anchor {
+            position: absolute;
+            left: -20px;
+            color: #8c959f;
+            opacity: 0;
+        }
+        article h1:hover .anchor, article h2:hover .anchor, article h3:hover .anchor, article h4:hover .anchor {
+            opacity: 1;
+        }
+        article p, article ul, article ol, article pre, article table, article blockquote {
+            margin-bottom: 16px;
+        }
+        article ul, article ol {
+            padding-left: 2em;
+        }
+        article li > ul, article li > ol {
+            margin-bottom: 0;
+        }
+        article li.task {
+            list-style: none;
+            margin-left: -1.4em;
+        }
+        article code {
+            background: rgba(175, 184, 193, 0.2);
+            padding: 0.2em 0.4em;
+            border-radius: 6px;
+            font-size: 85%;
+            font-family: ui-monospace, SFMono-Regular, 'SF Mono', Menlo, Consolas, monospace;
+        }
+        article pre {
+            background: #f6f8fa;
+            border-radius: 6px;
+            padding: 16px;
+            overflow-x: auto;
+            line-height: 1.45;
+        }
+        article pre code {
+            background: none;
+            padding: 0;
+            font-size: 85%;
+        }
+        article table {
+            border-collapse: collapse;
+            display: block;
+            overflow-x: auto;
+        }
+        article th, article td {
+            border: 1px solid #d0d7de;
+            padding: 6px 13px;
+        }
+        article tr:nth-child(2n) {
+            background: #f6f8fa;
+        }
+        article blockquote {
+            border-left: 4px solid #d0d7de;
+            color: #57606a;
+            padding: 0 1em;
+        }
+        article hr {
+            border: none;
+            border-top: 1px solid #d0d7de;
+            margin: 24px 0;
+        }
+        article img {
+            max-width: 100%;
+            vertical-align: middle;
+        }
+        .result {
+            margin-bottom: 18px;
+        }
+        .result .where {
+            color: #57606a;
+            font-size: 0.85em;
+        }
+        mark {
+            background: #fff8c5;
+        }
+        .doc-list li {
+            list-style: none;
+            margin-bottom: 12px;
+        }
+        .doc-list .description {
+            color: #57606a;
+            font-size: 0.92em;
+        }
+        @media (max-width: 800px) {
+            body {
+                display: block;
+            }
+            nav {
+                width: auto;
+                height: auto;
+                position: static;
+            }
+            main, article {
+                padding: 15px;
+            }
+        }
+    </style>
+</head>
+<body>
+    <nav>
+        <a class="brand" href="{{.Root}}">📖 Documentation</a>
+        <form action="{{.Root}}search" method="get">
+            <input type="search" name="q" value="{{.Query}}" placeholder="Search the docs" aria-label="Search the docs">
+        </form>
+        {{range .Groups}}
+        <h2>{{.Name}}</h2>
+        <ul>
+            {{range .Docs}}<li><a href="{{$.Root}}{{.Path}}"{{if eq .Path $.Current}} class="current"{{end}}>{{.Title}}</a></li>
+            {{end}}
+        </ul>
+        {{end}}
+        <a class="back" href="{{.Root}}../">← Back to the welcome page</a>
+    </nav>
+    <main>
+        <article>
+        {{if .Doc}}
+            <p class="source">hot-reload-template/{{.Doc.Path}} · bundled with this container</p>
+            {{if gt (len .TOC) 3}}
+            <details class="toc">
+                <summary>On this page</summary>
+                <ul>
+                    {{range .TOC}}<li class="level-{{.Level}}"><a href="#{{.ID}}">{{.Text}}</a></li>
+                    {{end}}
+                </ul>
+            </details>
+            {{end}}
+            {{.Doc.HTML}}
+        {{else if .Search}}
+            <h1>Search</h1>
+            {{if not .Query}}
+            <p>Type a word or phrase in the search box; sections containing every word are listed.</p>
+            {{else if .Results}}
+            <p class="source">{{len .Results}} section{{if gt (len .Results) 1}}s{{end}} matching “{{.Query}}”</p>
+            {{range .Results}}
+            <div class="result">
+                <a href="{{$.Root}}{{.Path}}{{if .Anchor}}#{{.Anchor}}{{end}}"><strong>{{if .Heading}}{{.Heading}}{{else}}{{.Title}}{{end}}</strong></a>
+                <div class="where">{{.Title}} · {{.Path}}</div>
+                <div>{{.Snippet}}</div>
+            </div>
+            {{end}}
+            {{else}}
+            <p>No sections match “{{.Query}}”.</p>
+            {{end}}
+        {{else}}
+            <h1>Documentation</h1>
+            {{if .Bundled}}
+            <p class="source">The template's guides and app example READMEs, bundled with this container so they match its version and work without GitHub.</p>
+            {{range .Groups}}
+            <h2>{{.Name}}</h2>
+            <ul class="doc-list">
+                {{range .Docs}}<li><a href="{{$.Root}}{{.Path}}"><strong>{{.Title}}</strong></a><div class="description">{{.Description}}</div></li>
+                {{end}}
+            </ul>
+            {{end}}
+            {{else}}
+            <p>No documentation was bundled into this build. Run <code>go generate</code> in <code>scripts/welcome-page-server</code> before <code>go build</code>; the Dockerfile does this for you.</p>
+            {{end}}
+        {{end}}
+        </article>
+    </main>
+</body>
+</html>
+`
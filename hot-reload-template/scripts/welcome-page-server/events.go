@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syncEventsFile mirrors SYNC_EVENTS_FILE in github-sync.sh
+const syncEventsFile = "/tmp/dev-sync-events.jsonl"
+
+// jobStateDir mirrors JOB_STATE_DIR in job-manager.sh
+const jobStateDir = "/tmp/dev-jobs"
+
+const (
+	activityPollInterval = time.Second
+	maxActivityEvents    = 100
+	subscriberBuffer     = 32
+	sseHeartbeat         = 15 * time.Second
+)
+
+// Activity event levels, used by the page to colour each entry
+const (
+	levelInfo    = "info"
+	levelSuccess = "success"
+	levelError   = "error"
+)
+
+// ActivityEvent is one entry of the live activity feed
+type ActivityEvent struct {
+	ID      int64  `json:"id"`
+	Type    string `json:"type"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Commit  string `json:"commit,omitempty"`
+	Time    string `json:"time"`
+}
+
+// syncEventRecord is one line of the sync event log written by github-sync.sh
+type syncEventRecord struct {
+	Event   string `json:"event"`
+	Commit  string `json:"commit"`
+	Message string `json:"message"`
+	Time    string `json:"time"`
+}
+
+// jobState is the subset of /tmp/dev-jobs/<JOB>.json the feed reports on
+type jobState struct {
+	JobType         string `json:"job_type"`
+	Commit          string `json:"commit"`
+	Status          string `json:"status"`
+	ExitCode        *int   `json:"exit_code"`
+	StartedAt       string `json:"started_at"`
+	DurationSeconds int64  `json:"duration_seconds"`
+}
+
+// activityFeed collects sync, job and app events from the files the scripts
+// write and fans them out to /events subscribers. Recent events are kept so a
+// page that (re)connects sees what it missed.
+type activityFeed struct {
+	mu          sync.Mutex
+	nextID      int64
+	events      []ActivityEvent
+	subscribers map[chan ActivityEvent]bool
+
+	// Watcher state, only touched by Watch
+	syncOffset int64
+	jobs       map[string]string
+	phase      string
+}
+
+// activity is the global feed shared by the handlers and the proxy
+var activity = &activityFeed{
+	nextID:      1,
+	subscribers: make(map[chan ActivityEvent]bool),
+	jobs:        make(map[string]string),
+}
+
+// Publish stamps an event with the next ID and sends it to every subscriber.
+// A subscriber that cannot keep up is dropped; its browser reconnects with
+// Last-Event-ID and catches up from the buffer.
+func (f *activityFeed) Publish(event ActivityEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	event.ID = f.nextID
+	f.nextID++
+	if event.Time == "" {
+		event.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	event.Message = redactText(event.Message)
+
+	f.events = append(f.events, event)
+	if len(f.events) > maxActivityEvents {
+		f.events = f.events[len(f.events)-maxActivityEvents:]
+	}
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the buffered events after afterID and a channel for new ones
+func (f *activityFeed) Subscribe(afterID int64) ([]ActivityEvent, chan ActivityEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var backlog []ActivityEvent
+	for _, event := range f.events {
+		if event.ID > afterID {
+			backlog = append(backlog, event)
+		}
+	}
+	ch := make(chan ActivityEvent, subscriberBuffer)
+	f.subscribers[ch] = true
+	return backlog, ch
+}
+
+// Unsubscribe stops sending to ch; it is safe to call after Publish dropped it
+func (f *activityFeed) Unsubscribe(ch chan ActivityEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.subscribers[ch] {
+		delete(f.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Last returns the most recent buffered event whose type starts with prefix
+func (f *activityFeed) Last(prefix string) *ActivityEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.events) - 1; i >= 0; i-- {
+		if strings.HasPrefix(f.events[i].Type, prefix) {
+			event := f.events[i]
+			return &event
+		}
+	}
+	return nil
+}
+
+// Watch polls the sync event log, job state files and startup phases. The
+// sync log is replayed from the start; jobs and phases only report changes.
+func (f *activityFeed) Watch(ctx context.Context) {
+	f.pollJobs(true)
+	f.phase = readCurrentPhase()
+
+	ticker := time.NewTicker(activityPollInterval)
+	defer ticker.Stop()
+	for {
+		f.pollSyncEvents()
+		f.pollJobs(false)
+		f.pollPhase()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollSyncEvents publishes lines appended to the sync event log since the last poll
+func (f *activityFeed) pollSyncEvents() {
+	file, err := os.Open(syncEventsFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error reading sync events: %v", err)
+		}
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < f.syncOffset {
+		// The file was recreated; start over
+		f.syncOffset = 0
+	}
+	if info.Size() == f.syncOffset {
+		return
+	}
+	if _, err := file.Seek(f.syncOffset, io.SeekStart); err != nil {
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return
+	}
+
+	// Leave a partially written last line for the next poll
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return
+	}
+	f.syncOffset += int64(end + 1)
+
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		var record syncEventRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		f.Publish(syncEvent(record))
+	}
+}
+
+// syncEvent turns a sync log record into a feed entry
+func syncEvent(record syncEventRecord) ActivityEvent {
+	event := ActivityEvent{Type: "sync_" + record.Event, Level: levelInfo, Commit: record.Commit, Time: record.Time}
+	switch record.Event {
+	case "commit_detected":
+		event.Message = fmt.Sprintf("New commit %s detected, pulling", shortCommit(record.Commit))
+	case "pulled":
+		event.Level = levelSuccess
+		event.Message = fmt.Sprintf("Pulled commit %s", shortCommit(record.Commit))
+	case "failed":
+		event.Level = levelError
+		event.Message = "Sync failed"
+	default:
+		event.Message = "Sync: " + record.Event
+	}
+	if record.Message != "" {
+		event.Message += ": " + record.Message
+	}
+	return event
+}
+
+// pollJobs publishes job starts and finishes. The first poll only records the
+// current state, so restarting the server does not replay old runs.
+func (f *activityFeed) pollJobs(baseline bool) {
+	files, err := filepath.Glob(filepath.Join(jobStateDir, "*.json"))
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var job jobState
+		if err := json.Unmarshal(data, &job); err != nil || job.JobType == "" {
+			continue
+		}
+		key := job.Status + "@" + job.StartedAt
+		if f.jobs[job.JobType] == key {
+			continue
+		}
+		f.jobs[job.JobType] = key
+		if !baseline {
+			f.Publish(jobEvent(job))
+		}
+	}
+}
+
+// jobEvent turns a job state change into a feed entry
+func jobEvent(job jobState) ActivityEvent {
+	event := ActivityEvent{Type: "job_finished", Level: levelError, Commit: job.Commit}
+	duration := time.Duration(job.DurationSeconds) * time.Second
+	switch job.Status {
+	case "running":
+		event.Type, event.Level = "job_started", levelInfo
+		event.Message = fmt.Sprintf("%s job started for commit %s", job.JobType, shortCommit(job.Commit))
+	case "success":
+		event.Level = levelSuccess
+		event.Message = fmt.Sprintf("%s job succeeded in %s", job.JobType, duration)
+	case "timeout":
+		event.Message = fmt.Sprintf("%s job timed out after %s", job.JobType, duration)
+	default:
+		event.Message = fmt.Sprintf("%s job %s after %s", job.JobType, job.Status, duration)
+		if job.ExitCode != nil {
+			event.Message += fmt.Sprintf(" (exit code %d)", *job.ExitCode)
+		}
+	}
+	return event
+}
+
+// pollPhase publishes startup phase changes, including the app being started
+func (f *activityFeed) pollPhase() {
+	phase := readCurrentPhase()
+	if phase == f.phase || phase == "" {
+		return
+	}
+	f.phase = phase
+
+	event := ActivityEvent{Type: "startup_phase", Level: levelInfo}
+	switch phase {
+	case "app":
+		event.Type = "app_started"
+		event.Message = "Starting your app"
+	case "no_app":
+		event.Message = "Startup finished; no app to start yet"
+	default:
+		event.Message = "Container startup: " + phase
+	}
+	f.Publish(event)
+}
+
+// shortCommit abbreviates a commit SHA for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	if commit == "" {
+		return "unknown"
+	}
+	return commit
+}
+
+// eventsHandler streams the activity feed as server-sent events
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /events endpoint
+	if r.URL.Path != "/events" {
+		http.NotFound(w, r)
+		return
+	}
+
+	// The server's write timeout would otherwise end the stream after a few seconds
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("Error disabling write deadline for /events: %v", err)
+	}
+
+	// Browsers resend the last ID they saw when reconnecting
+	lastID, _ := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	backlog, ch := activity.Subscribe(lastID)
+	defer activity.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	for _, event := range backlog {
+		writeSSE(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeSSE(w, event)
+		case <-heartbeat.C:
+			// Comment lines keep proxies from closing an idle stream
+			fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE writes one event in text/event-stream framing
+func writeSSE(w io.Writer, event ActivityEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error encoding event: %v", err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+}
@@ -0,0 +1,647 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGitCommits = 30
+	maxGitCommits     = 200
+	maxDiffBytes      = 512 * 1024
+	gitTimeout        = 10 * time.Second
+)
+
+// emptyTreeSHA is git's empty tree, the "previous" side of a root commit's diff
+const emptyTreeSHA = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// shaPattern accepts full and abbreviated commit SHAs, and nothing git could
+// mistake for an option or a revision expression
+var shaPattern = regexp.MustCompile(`^[0-9a-fA-F]{4,40}$`)
+
+// GitCommit is one entry of the commit list
+type GitCommit struct {
+	SHA         string `json:"sha"`
+	ShortSHA    string `json:"short_sha"`
+	Parent      string `json:"parent,omitempty"`
+	Author      string `json:"author"`
+	Time        string `json:"time"`
+	Subject     string `json:"subject"`
+	Deployed    bool   `json:"deployed"`
+	PrevDeploy  bool   `json:"previous_deployed"`
+	DisplayTime string `json:"-"`
+}
+
+// GitCommitsResponse is the JSON response for the /api/git/commits endpoint
+type GitCommitsResponse struct {
+	RepoPath         string      `json:"repo_path"`
+	Folder           string      `json:"folder,omitempty"`
+	Branch           string      `json:"branch,omitempty"`
+	Deployed         string      `json:"deployed,omitempty"`
+	PreviousDeployed string      `json:"previous_deployed,omitempty"`
+	Commits          []GitCommit `json:"commits"`
+	Error            string      `json:"error,omitempty"`
+}
+
+// GitFileChange is one file in a diff
+type GitFileChange struct {
+	Path      string `json:"path"`
+	OldPath   string `json:"old_path,omitempty"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+}
+
+// GitDiffResponse is the JSON response for the /api/git/diff endpoint
+type GitDiffResponse struct {
+	RepoPath  string          `json:"repo_path"`
+	Folder    string          `json:"folder,omitempty"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Files     []GitFileChange `json:"files"`
+	Diff      string          `json:"diff"`
+	Truncated bool            `json:"truncated"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// gitRepo is the checkout github-sync.sh keeps up to date and, for monorepos,
+// the folder the app is synced from
+type gitRepo struct {
+	path   string
+	folder string
+}
+
+// currentGitRepo returns the workspace repository or the monorepo cache
+func currentGitRepo() gitRepo {
+	src := currentConfig()
+	return gitRepo{path: repoCheckoutPath(src), folder: strings.Trim(src.get("GITHUB_REPO_FOLDER"), "/")}
+}
+
+// git runs a git command in the repository and returns its output. The
+// checkout may belong to another user, so safe.directory is set for it.
+func (g gitRepo) git(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-c", "safe.directory=" + g.path, "-C", g.path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
+
+// pathspec limits log and diff to the monorepo folder
+func (g gitRepo) pathspec() []string {
+	if g.folder == "" {
+		return nil
+	}
+	return []string{"--", g.folder}
+}
+
+// deployedCommits returns HEAD and the commit HEAD was on before the last
+// sync moved it, read from the reflog every pull and reset writes to. A fresh
+// clone has no earlier entry, so HEAD's parent stands in.
+func (g gitRepo) deployedCommits() (current, previous string, err error) {
+	output, err := g.git("reflog", "-n", "50", "--format=%H")
+	if err != nil {
+		return "", "", err
+	}
+	for _, sha := range strings.Fields(output) {
+		if current == "" {
+			current = sha
+		} else if sha != current {
+			return current, sha, nil
+		}
+	}
+	if current == "" {
+		return "", "", errors.New("the repository has no commits")
+	}
+	if parent, err := g.git("rev-parse", "--verify", "--quiet", current+"^"); err == nil {
+		return current, strings.TrimSpace(parent), nil
+	}
+	return current, emptyTreeSHA, nil
+}
+
+// commits lists the last limit commits on HEAD, marking the deployed ones
+func (g gitRepo) commits(limit int) GitCommitsResponse {
+	response := GitCommitsResponse{RepoPath: g.path, Folder: g.folder, Commits: []GitCommit{}}
+	deployed, previous, err := g.deployedCommits()
+	if err != nil {
+		response.Error = redactText(err.Error())
+		return response
+	}
+	response.Deployed, response.PreviousDeployed = deployed, previous
+	if branch, err := g.git("symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		response.Branch = strings.TrimSpace(branch)
+	}
+
+	// Fields are separated by the unit separator, which cannot appear in them
+	args := append([]string{"log", "-n", strconv.Itoa(limit), "--format=%H%x1f%P%x1f%an%x1f%aI%x1f%s"}, g.pathspec()...)
+	output, err := g.git(args...)
+	if err != nil {
+		response.Error = redactText(err.Error())
+		return response
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		commit := GitCommit{
+			SHA:        fields[0],
+			ShortSHA:   shortCommit(fields[0]),
+			Author:     fields[2],
+			Time:       fields[3],
+			Subject:    redactText(fields[4]),
+			Deployed:   fields[0] == deployed,
+			PrevDeploy: fields[0] == previous,
+		}
+		commit.Parent, _, _ = strings.Cut(fields[1], " ")
+		if commit.Parent == "" {
+			commit.Parent = emptyTreeSHA
+		}
+		if t, err := time.Parse(time.RFC3339, commit.Time); err == nil {
+			commit.DisplayTime = t.UTC().Format("2006-01-02 15:04 UTC")
+		}
+		response.Commits = append(response.Commits, commit)
+	}
+	return response
+}
+
+// head returns the deployed commit, or nil when the repository cannot be read
+func (g gitRepo) head() *GitCommit {
+	output, err := g.git("log", "-1", "--format=%H%x1f%s")
+	if err != nil {
+		return nil
+	}
+	sha, subject, _ := strings.Cut(strings.TrimSpace(output), "\x1f")
+	return &GitCommit{SHA: sha, ShortSHA: shortCommit(sha), Subject: redactText(subject), Deployed: true}
+}
+
+// diff compares two commits: the changed files with line counts, and the
+// patch itself up to maxDiffBytes
+func (g gitRepo) diff(from, to string) GitDiffResponse {
+	response := GitDiffResponse{RepoPath: g.path, Folder: g.folder, From: from, To: to, Files: []GitFileChange{}}
+
+	nameStatus, err := g.git(append([]string{"diff", "-M", "-z", "--name-status", from, to}, g.pathspec()...)...)
+	if err != nil {
+		response.Error = redactText(err.Error())
+		return response
+	}
+	numstat, err := g.git(append([]string{"diff", "-M", "-z", "--numstat", from, to}, g.pathspec()...)...)
+	if err != nil {
+		response.Error = redactText(err.Error())
+		return response
+	}
+	response.Files = parseFileChanges(nameStatus, numstat)
+
+	response.Diff, response.Truncated, err = g.patch(from, to)
+	if err != nil {
+		response.Error = redactText(err.Error())
+	}
+	return response
+}
+
+// patch returns the unified diff, stopping after maxDiffBytes so a vendored
+// directory or lock file rewrite cannot exhaust memory
+func (g gitRepo) patch(from, to string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+	args := append([]string{"-c", "safe.directory=" + g.path, "-C", g.path, "diff", "-M", "--no-color", from, to}, g.pathspec()...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return "", false, err
+	}
+	data, err := io.ReadAll(io.LimitReader(stdout, maxDiffBytes+1))
+	truncated := len(data) > maxDiffBytes
+	if truncated {
+		// Stop git instead of reading the rest of the patch
+		cancel()
+		data = data[:maxDiffBytes]
+		if cut := bytes.LastIndexByte(data, '\n'); cut >= 0 {
+			data = data[:cut+1]
+		}
+	}
+	waitErr := cmd.Wait()
+	if err == nil && !truncated {
+		err = waitErr
+	}
+	return redactText(string(data)), truncated, err
+}
+
+// parseFileChanges combines "diff -z --name-status" and "diff -z --numstat".
+// Renames take two paths in both: "R100\0old\0new\0" and "1\t2\t\0old\0new\0".
+func parseFileChanges(nameStatus, numstat string) []GitFileChange {
+	type counts struct {
+		additions, deletions int
+		binary               bool
+	}
+	stats := make(map[string]counts)
+	fields := strings.Split(numstat, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		if path == "" && i+2 < len(fields) {
+			path = fields[i+2]
+			i += 2
+		}
+		c := counts{binary: parts[0] == "-"}
+		c.additions, _ = strconv.Atoi(parts[0])
+		c.deletions, _ = strconv.Atoi(parts[1])
+		stats[path] = c
+	}
+
+	changes := []GitFileChange{}
+	fields = strings.Split(nameStatus, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status := fields[i]
+		if status == "" {
+			continue
+		}
+		change := GitFileChange{Status: status[:1], Path: fields[i+1]}
+		if (change.Status == "R" || change.Status == "C") && i+2 < len(fields) {
+			change.OldPath, change.Path = fields[i+1], fields[i+2]
+			i++
+		}
+		c := stats[change.Path]
+		change.Additions, change.Deletions, change.Binary = c.additions, c.deletions, c.binary
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// gitQuery holds the validated query parameters shared by the git endpoints
+type gitQuery struct {
+	limit    int
+	from, to string
+}
+
+// parseGitQuery reads limit, from and to; from and to must be commit SHAs
+func parseGitQuery(r *http.Request) (gitQuery, error) {
+	query := gitQuery{limit: defaultGitCommits}
+	values := r.URL.Query()
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return query, fmt.Errorf("invalid limit %q", value)
+		}
+		query.limit = min(limit, maxGitCommits)
+	}
+	for name, target := range map[string]*string{"from": &query.from, "to": &query.to} {
+		value := values.Get(name)
+		if value != "" && !shaPattern.MatchString(value) {
+			return query, fmt.Errorf("invalid %s %q: expected a commit SHA", name, value)
+		}
+		*target = value
+	}
+	return query, nil
+}
+
+// diffRange fills in the previous and current deployed commits when from or to is missing
+func diffRange(repo gitRepo, query gitQuery) (string, string, error) {
+	if query.from != "" && query.to != "" {
+		return query.from, query.to, nil
+	}
+	deployed, previous, err := repo.deployedCommits()
+	if err != nil {
+		return "", "", err
+	}
+	from, to := query.from, query.to
+	if to == "" {
+		to = deployed
+	}
+	if from == "" {
+		from = previous
+	}
+	return from, to, nil
+}
+
+// gitCommitsHandler handles requests to the /api/git/commits endpoint
+func gitCommitsHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/git/commits endpoint
+	if r.URL.Path != "/api/git/commits" {
+		http.NotFound(w, r)
+		return
+	}
+	query, err := parseGitQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(currentGitRepo().commits(query.limit)); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// gitDiffHandler handles requests to the /api/git/diff endpoint; without
+// from and to it compares the previous and current deployed commits
+func gitDiffHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/git/diff endpoint
+	if r.URL.Path != "/api/git/diff" {
+		http.NotFound(w, r)
+		return
+	}
+	query, err := parseGitQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repo := currentGitRepo()
+	response := GitDiffResponse{RepoPath: repo.path, Folder: repo.folder, Files: []GitFileChange{}}
+	if from, to, err := diffRange(repo, query); err != nil {
+		response.Error = redactText(err.Error())
+	} else {
+		response = repo.diff(from, to)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// GitPageData holds data for the git browser template
+type GitPageData struct {
+	Commits   GitCommitsResponse
+	Diff      GitDiffResponse
+	DiffLines []diffLine
+	Deployed  bool
+	Timestamp string
+}
+
+// diffLine is one line of the rendered patch
+type diffLine struct {
+	Class  string
+	Text   string
+	Anchor string
+}
+
+// splitDiff classifies patch lines for colouring and anchors each file's header
+func splitDiff(patch string) []diffLine {
+	var lines []diffLine
+	file := 0
+	for _, text := range strings.Split(strings.TrimSuffix(patch, "\n"), "\n") {
+		line := diffLine{Text: text}
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			line.Class = "diff-file"
+			line.Anchor = fmt.Sprintf("file-%d", file)
+			file++
+		case strings.HasPrefix(text, "+++ "), strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "index "):
+			line.Class = "diff-meta"
+		case strings.HasPrefix(text, "@@"):
+			line.Class = "diff-hunk"
+		case strings.HasPrefix(text, "+"):
+			line.Class = "diff-add"
+		case strings.HasPrefix(text, "-"):
+			line.Class = "diff-del"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// gitPageHandler renders the commit list and a diff, by default the one the last sync brought in
+func gitPageHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /git endpoint
+	if r.URL.Path != "/git" {
+		http.NotFound(w, r)
+		return
+	}
+	query, err := parseGitQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	repo := currentGitRepo()
+	data := GitPageData{
+		Commits:   repo.commits(query.limit),
+		Deployed:  query.from == "" && query.to == "",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if data.Commits.Error == "" {
+		if from, to, err := diffRange(repo, query); err != nil {
+			data.Diff.Error = redactText(err.Error())
+		} else {
+			data.Diff = repo.diff(from, to)
+			data.DiffLines = splitDiff(data.Diff.Diff)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if err := gitPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Error executing template: %v", err)
+	}
+}
+
+// gitPageTemplate is the workspace git browser
+var gitPageTemplate = template.Must(template.New("git").Funcs(template.FuncMap{"short": shortCommit}).Parse(gitPageHTML))
+
+// gitPageHTML is the HTML template for the git browser
+const gitPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Workspace Commits</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            background: #1e1e1e;
+            color: #d4d4d4;
+            padding: 20px;
+        }
+        a {
+            color: #9cdcfe;
+        }
+        h1 {
+            font-size: 1.4em;
+            margin-bottom: 6px;
+        }
+        h2 {
+            font-size: 1.1em;
+            margin: 25px 0 10px;
+        }
+        .subtitle {
+            color: #888;
+            font-size: 0.9em;
+        }
+        .error {
+            background: #5a1d1d;
+            border-left: 4px solid #f48771;
+            padding: 10px 15px;
+            margin: 15px 0;
+            border-radius: 4px;
+        }
+        table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 0.9em;
+        }
+        td, th {
+            text-align: left;
+            padding: 6px 8px;
+            border-bottom: 1px solid #333;
+            vertical-align: top;
+        }
+        th {
+            color: #888;
+            font-weight: normal;
+        }
+        tr.deployed {
+            background: #1f3a24;
+        }
+        tr.previous {
+            background: #2d2d2d;
+        }
+        .mono {
+            font-family: 'Courier New', monospace;
+        }
+        .badge {
+            display: inline-block;
+            padding: 1px 8px;
+            border-radius: 10px;
+            font-size: 0.8em;
+            margin-left: 6px;
+            background: #388a34;
+            color: white;
+        }
+        .badge.previous {
+            background: #555;
+        }
+        .files li {
+            list-style: none;
+            padding: 2px 0;
+            font-family: 'Courier New', monospace;
+            font-size: 0.9em;
+        }
+        .status {
+            display: inline-block;
+            width: 1.5em;
+            font-weight: bold;
+        }
+        .status-A, .additions {
+            color: #89d185;
+        }
+        .status-D, .deletions {
+            color: #f48771;
+        }
+        .status-M, .status-R {
+            color: #dcdcaa;
+        }
+        .diff {
+            background: #252526;
+            border-radius: 4px;
+            padding: 10px 0;
+            overflow-x: auto;
+            font-family: 'Courier New', monospace;
+            font-size: 0.85em;
+            line-height: 1.4;
+        }
+        .diff div {
+            padding: 0 12px;
+            white-space: pre;
+        }
+        .diff-file {
+            color: #fff;
+            font-weight: bold;
+            background: #333;
+            margin-top: 10px;
+        }
+        .diff-meta {
+            color: #888;
+        }
+        .diff-hunk {
+            color: #4fc1ff;
+        }
+        .diff-add {
+            background: #1f3a24;
+            color: #b5e8b0;
+        }
+        .diff-del {
+            background: #4b1f1f;
+            color: #f8c4bb;
+        }
+    </style>
+</head>
+<body>
+    <h1>📜 Workspace Commits</h1>
+    <p class="subtitle"><span class="mono">{{.Commits.RepoPath}}</span>{{if .Commits.Folder}} · folder <span class="mono">{{.Commits.Folder}}</span>{{end}}{{if .Commits.Branch}} · branch <span class="mono">{{.Commits.Branch}}</span>{{end}} · <a href="./">back to the welcome page</a> · <a href="logs">logs</a></p>
+
+    {{if .Commits.Error}}
+    <div class="error">Cannot read the repository: {{.Commits.Error}}</div>
+    {{else}}
+    <h2>Recent commits</h2>
+    <table>
+        <tr><th>Commit</th><th>Message</th><th>Author</th><th>Time</th><th></th></tr>
+        {{range .Commits.Commits}}
+        <tr class="{{if .Deployed}}deployed{{else if .PrevDeploy}}previous{{end}}">
+            <td class="mono">{{.ShortSHA}}{{if .Deployed}}<span class="badge">deployed</span>{{else if .PrevDeploy}}<span class="badge previous">previously deployed</span>{{end}}</td>
+            <td>{{.Subject}}</td>
+            <td>{{.Author}}</td>
+            <td class="mono">{{.DisplayTime}}</td>
+            <td><a href="git?from={{.Parent}}&amp;to={{.SHA}}">changes</a></td>
+        </tr>
+        {{end}}
+    </table>
+
+    <h2>{{if .Deployed}}What the last sync brought in{{else}}Changes{{end}}: <span class="mono">{{short .Diff.From}}</span> → <span class="mono">{{short .Diff.To}}</span>{{if not .Deployed}} · <a href="git">show the deployed changes</a>{{end}}</h2>
+    {{if .Diff.Error}}
+    <div class="error">Cannot compare the commits: {{.Diff.Error}}</div>
+    {{else if not .Diff.Files}}
+    <p class="subtitle">No files changed{{if .Commits.Folder}} in {{.Commits.Folder}}{{end}}.</p>
+    {{else}}
+    <ul class="files">
+        {{range $i, $file := .Diff.Files}}
+        <li><span class="status status-{{$file.Status}}">{{$file.Status}}</span><a href="#file-{{$i}}">{{if $file.OldPath}}{{$file.OldPath}} → {{end}}{{$file.Path}}</a> {{if $file.Binary}}<span class="subtitle">binary</span>{{else}}<span class="additions">+{{$file.Additions}}</span> <span class="deletions">-{{$file.Deletions}}</span>{{end}}</li>
+        {{end}}
+    </ul>
+    <h2>Diff</h2>
+    {{if .Diff.Truncated}}<p class="subtitle">The diff is too large to show in full; it was cut off after 512 KB.</p>{{end}}
+    <div class="diff">{{range .DiffLines}}<div{{if .Class}} class="{{.Class}}"{{end}}{{if .Anchor}} id="{{.Anchor}}"{{end}}>{{.Text}}</div>{{end}}</div>
+    {{end}}
+    {{end}}
+    <p class="subtitle" style="margin-top: 20px;">Generated at {{.Timestamp}} · also available as JSON at <a href="api/git/commits">api/git/commits</a> and <a href="api/git/diff">api/git/diff</a></p>
+</body>
+</html>
+`
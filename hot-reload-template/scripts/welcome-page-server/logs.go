@@ -0,0 +1,674 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLogDir mirrors DEV_LOG_DIR in dev-log-capture.sh
+const defaultLogDir = "/tmp/dev-logs"
+
+const (
+	defaultLogLines = 200
+	maxLogLines     = 2000
+	// maxTailBytes bounds how much of each file is read to find the last lines
+	maxTailBytes = 4 << 20
+)
+
+// Log levels, from least to most severe
+const (
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+)
+
+var logLevelRank = map[string]int{logLevelInfo: 0, logLevelWarn: 1, logLevelError: 2}
+
+// defaultLogSources are always listed, even before they have written anything
+var defaultLogSources = []string{"app", "sync", "jobs"}
+
+var (
+	logSourcePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// ansiPattern matches CSI sequences (colors, cursor movement) and OSC sequences (titles, links)
+	ansiPattern     = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+	errorWordRegexp = regexp.MustCompile(`(?i)(error|exception)\b|\b(fatal|panic)\b`)
+	warnWordRegexp  = regexp.MustCompile(`(?i)\b(warn|warning|deprecated)\b`)
+)
+
+// LogLine is one captured line
+type LogLine struct {
+	Time   string `json:"time"`
+	Source string `json:"source"`
+	Level  string `json:"level"`
+	Text   string `json:"text"`
+	HTML   string `json:"html"`
+}
+
+// LogSource describes one captured stream
+type LogSource struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"size_bytes"`
+	Available bool   `json:"available"`
+}
+
+// LogsResponse is the JSON response for /api/logs
+type LogsResponse struct {
+	Sources []LogSource `json:"sources"`
+	Lines   []LogLine   `json:"lines"`
+}
+
+// logQuery holds the filters of a /api/logs request
+type logQuery struct {
+	sources  []string
+	minLevel string
+	search   string
+	lines    int
+}
+
+// logDir returns DEV_LOG_DIR or the default directory
+func logDir() string {
+	return getEnvOrDefault("DEV_LOG_DIR", defaultLogDir)
+}
+
+// listLogSources returns the default sources plus any other captured stream
+func listLogSources() []LogSource {
+	names := append([]string(nil), defaultLogSources...)
+	files, _ := filepath.Glob(filepath.Join(logDir(), "*.log"))
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".log")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	sources := make([]LogSource, 0, len(names))
+	for _, name := range names {
+		source := LogSource{Name: name}
+		if info, err := os.Stat(filepath.Join(logDir(), name+".log")); err == nil {
+			source.SizeBytes = info.Size()
+			source.Available = true
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+// readLogs returns the last lines of the selected sources that pass the
+// filters, merged in time order
+func readLogs(query logQuery) []LogLine {
+	var merged []LogLine
+	for _, source := range query.sources {
+		merged = append(merged, tailLogSource(source, query)...)
+	}
+	// Timestamps have second resolution; the stable sort keeps each file's order
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Time < merged[j].Time })
+	if len(merged) > query.lines {
+		merged = merged[len(merged)-query.lines:]
+	}
+	return merged
+}
+
+// tailLogSource reads matching lines from the newest end of a source's files,
+// continuing into the most recently rotated file when the current one is short
+func tailLogSource(source string, query logQuery) []LogLine {
+	base := filepath.Join(logDir(), source+".log")
+	var lines []LogLine
+	for _, file := range []string{base, base + ".1"} {
+		raw, err := tailFile(file, maxTailBytes)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("Error reading log %s: %v", file, err)
+			}
+			break
+		}
+		var matched []LogLine
+		for _, entry := range raw {
+			line := parseLogLine(source, entry)
+			if logLevelRank[line.Level] < logLevelRank[query.minLevel] {
+				continue
+			}
+			if query.search != "" && !strings.Contains(strings.ToLower(line.Text), query.search) {
+				continue
+			}
+			matched = append(matched, line)
+		}
+		lines = append(matched, lines...)
+		if len(lines) >= query.lines {
+			break
+		}
+	}
+	if len(lines) > query.lines {
+		lines = lines[len(lines)-query.lines:]
+	}
+	return lines
+}
+
+// tailFile returns the complete lines in the last maxBytes of a file
+func tailFile(path string, maxBytes int64) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - maxBytes
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		// Drop the partial first line
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// parseLogLine splits "<timestamp> <text>" as written by dev-log-capture.
+// Credentials are masked before the line is converted for display.
+func parseLogLine(source, entry string) LogLine {
+	line := LogLine{Source: source}
+	if stamp, text, ok := strings.Cut(entry, " "); ok && len(stamp) == len("2006-01-02T15:04:05Z") {
+		line.Time, entry = stamp, text
+	}
+
+	// Progress bars redraw with \r; only the final state is interesting
+	if i := strings.LastIndex(strings.TrimRight(entry, "\r"), "\r"); i >= 0 {
+		entry = entry[i+1:]
+	}
+	entry = redactText(strings.TrimRight(entry, "\r"))
+
+	line.Level = detectLogLevel(entry)
+	line.Text = stripANSI(entry)
+	line.HTML = ansiToHTML(entry)
+	return line
+}
+
+// detectLogLevel uses the color the scripts print their tag in ([INFO] green,
+// [WARN] yellow, [ERROR] red), falling back to keywords for app output
+func detectLogLevel(entry string) string {
+	if strings.HasPrefix(entry, "\x1b[") {
+		if end := strings.IndexByte(entry, 'm'); end > 0 {
+			for _, param := range strings.Split(entry[2:end], ";") {
+				switch param {
+				case "31", "91":
+					return logLevelError
+				case "33", "93":
+					return logLevelWarn
+				case "32", "92":
+					return logLevelInfo
+				}
+			}
+		}
+	}
+	text := stripANSI(entry)
+	switch {
+	case strings.HasPrefix(text, "[ERROR]") || errorWordRegexp.MatchString(text):
+		return logLevelError
+	case strings.HasPrefix(text, "[WARN]") || warnWordRegexp.MatchString(text):
+		return logLevelWarn
+	}
+	return logLevelInfo
+}
+
+// stripANSI removes terminal escape sequences
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// ansiPalette holds the 16 standard terminal colors (normal, then bright)
+var ansiPalette = [16]string{
+	"#4d4d4d", "#e06c75", "#98c379", "#e5c07b", "#61afef", "#c678dd", "#56b6c2", "#dcdfe4",
+	"#7f848e", "#ff7b86", "#b5e890", "#ffd68a", "#7cc4ff", "#e29efc", "#7fdbe6", "#ffffff",
+}
+
+// ansiStyle is the SGR state that applies to the text being converted
+type ansiStyle struct {
+	fg, bg                       string
+	bold, dim, italic, underline bool
+}
+
+// css renders the style as an inline style attribute value
+func (s ansiStyle) css() string {
+	var parts []string
+	if s.fg != "" {
+		parts = append(parts, "color:"+s.fg)
+	}
+	if s.bg != "" {
+		parts = append(parts, "background-color:"+s.bg)
+	}
+	if s.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.dim {
+		parts = append(parts, "opacity:0.7")
+	}
+	if s.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if s.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	return strings.Join(parts, ";")
+}
+
+// ansiToHTML converts SGR color sequences to styled spans and escapes the
+// text; any other escape sequence is dropped
+func ansiToHTML(s string) string {
+	var out strings.Builder
+	var style ansiStyle
+	open := false
+
+	last := 0
+	for _, loc := range ansiPattern.FindAllStringIndex(s, -1) {
+		out.WriteString(html.EscapeString(s[last:loc[0]]))
+		last = loc[1]
+
+		seq := s[loc[0]:loc[1]]
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			continue
+		}
+		style = applySGR(style, seq[2:len(seq)-1])
+		if open {
+			out.WriteString("</span>")
+			open = false
+		}
+		if css := style.css(); css != "" {
+			fmt.Fprintf(&out, `<span style="%s">`, css)
+			open = true
+		}
+	}
+	out.WriteString(html.EscapeString(s[last:]))
+	if open {
+		out.WriteString("</span>")
+	}
+	return out.String()
+}
+
+// applySGR updates a style with the parameters of one "ESC[...m" sequence
+func applySGR(style ansiStyle, params string) ansiStyle {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = 0 // an empty parameter means reset
+		}
+		switch {
+		case code == 0:
+			style = ansiStyle{}
+		case code == 1:
+			style.bold = true
+		case code == 2:
+			style.dim = true
+		case code == 3:
+			style.italic = true
+		case code == 4:
+			style.underline = true
+		case code == 22:
+			style.bold, style.dim = false, false
+		case code == 23:
+			style.italic = false
+		case code == 24:
+			style.underline = false
+		case code >= 30 && code <= 37:
+			style.fg = ansiPalette[code-30]
+		case code >= 90 && code <= 97:
+			style.fg = ansiPalette[code-90+8]
+		case code == 39:
+			style.fg = ""
+		case code >= 40 && code <= 47:
+			style.bg = ansiPalette[code-40]
+		case code >= 100 && code <= 107:
+			style.bg = ansiPalette[code-100+8]
+		case code == 49:
+			style.bg = ""
+		case code == 38 || code == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if code == 38 {
+				style.fg = color
+			} else {
+				style.bg = color
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor parses the arguments of a 38/48 sequence: "5;n" for the
+// 256-color palette or "2;r;g;b" for true color. It returns the CSS color
+// and how many parameters it consumed.
+func extendedColor(args []string) (string, int) {
+	if len(args) >= 2 && args[0] == "5" {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 || n > 255 {
+			return "", 2
+		}
+		switch {
+		case n < 16:
+			return ansiPalette[n], 2
+		case n < 232:
+			// 6x6x6 color cube
+			n -= 16
+			levels := [6]int{0, 95, 135, 175, 215, 255}
+			return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6]), 2
+		default:
+			gray := 8 + (n-232)*10
+			return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray), 2
+		}
+	}
+	if len(args) >= 4 && args[0] == "2" {
+		var rgb [3]int
+		for i := range rgb {
+			v, err := strconv.Atoi(args[i+1])
+			if err != nil || v < 0 || v > 255 {
+				return "", 4
+			}
+			rgb[i] = v
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]), 4
+	}
+	return "", len(args)
+}
+
+// parseLogQuery reads ?source=app,sync (repeatable), ?level=, ?q= and ?lines=
+func parseLogQuery(r *http.Request) (logQuery, error) {
+	params := r.URL.Query()
+	query := logQuery{minLevel: logLevelInfo, lines: defaultLogLines}
+
+	for _, value := range params["source"] {
+		for _, source := range strings.Split(value, ",") {
+			source = strings.TrimSpace(source)
+			if source == "" {
+				continue
+			}
+			if !logSourcePattern.MatchString(source) {
+				return query, fmt.Errorf("invalid source %q", source)
+			}
+			if !slices.Contains(query.sources, source) {
+				query.sources = append(query.sources, source)
+			}
+		}
+	}
+	if len(query.sources) == 0 {
+		for _, source := range listLogSources() {
+			query.sources = append(query.sources, source.Name)
+		}
+	}
+
+	if level := params.Get("level"); level != "" {
+		if _, ok := logLevelRank[level]; !ok {
+			return query, fmt.Errorf("invalid level %q (want info, warn or error)", level)
+		}
+		query.minLevel = level
+	}
+
+	if lines := params.Get("lines"); lines != "" {
+		n, err := strconv.Atoi(lines)
+		if err != nil || n < 1 {
+			return query, fmt.Errorf("invalid lines %q", lines)
+		}
+		query.lines = min(n, maxLogLines)
+	}
+
+	query.search = strings.ToLower(strings.TrimSpace(params.Get("q")))
+	return query, nil
+}
+
+// logsAPIHandler serves captured log lines as JSON
+func logsAPIHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/logs endpoint
+	if r.URL.Path != "/api/logs" {
+		http.NotFound(w, r)
+		return
+	}
+
+	query, err := parseLogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := LogsResponse{Sources: listLogSources(), Lines: readLogs(query)}
+	if response.Lines == nil {
+		response.Lines = []LogLine{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// logsPageHandler serves the log viewer; it loads lines from /api/logs
+func logsPageHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /logs endpoint
+	if r.URL.Path != "/logs" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := struct {
+		Sources []LogSource
+		LogDir  string
+	}{Sources: listLogSources(), LogDir: logDir()}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := logsPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Error executing template: %v", err)
+	}
+}
+
+// logsPageTemplate is the log viewer page
+var logsPageTemplate = template.Must(template.New("logs").Parse(logsPageHTML))
+
+// logsPageHTML is the HTML template for the log viewer
+const logsPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Logs - Dev Environment</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            background: #1e1e2e;
+            color: #dcdfe4;
+            display: flex;
+            flex-direction: column;
+            height: 100vh;
+        }
+        header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 12px 20px;
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 15px;
+        }
+        header h1 {
+            font-size: 1.2em;
+            margin-right: 10px;
+        }
+        header a {
+            color: white;
+        }
+        header label {
+            font-size: 0.9em;
+            white-space: nowrap;
+        }
+        header select, header input[type=search] {
+            padding: 4px 8px;
+            border-radius: 4px;
+            border: none;
+            font-size: 0.9em;
+        }
+        #status {
+            margin-left: auto;
+            font-size: 0.85em;
+            opacity: 0.9;
+        }
+        #log {
+            flex: 1;
+            overflow-y: auto;
+            padding: 10px 20px;
+            font-family: 'SF Mono', Menlo, 'Courier New', monospace;
+            font-size: 0.85em;
+            line-height: 1.45;
+            white-space: pre-wrap;
+            word-break: break-all;
+        }
+        .line-time {
+            color: #7f848e;
+        }
+        .line-source {
+            display: inline-block;
+            min-width: 5ch;
+            color: #c678dd;
+        }
+        .line-warn {
+            background: rgba(229, 192, 123, 0.08);
+        }
+        .line-error {
+            background: rgba(224, 108, 117, 0.12);
+        }
+        .empty {
+            color: #7f848e;
+            font-style: italic;
+        }
+    </style>
+</head>
+<body>
+    <header>
+        <h1>📜 Logs</h1>
+        {{range .Sources}}
+        <label><input type="checkbox" name="source" value="{{.Name}}" checked> {{.Name}}</label>
+        {{end}}
+        <label>Level
+            <select id="level">
+                <option value="info">all</option>
+                <option value="warn">warnings and errors</option>
+                <option value="error">errors only</option>
+            </select>
+        </label>
+        <input type="search" id="search" placeholder="Filter text">
+        <label><input type="checkbox" id="follow" checked> Follow</label>
+        <span id="status">Loading…</span>
+        <a href="./">Back to status page</a>
+    </header>
+    <div id="log"><span class="empty">No output captured yet. Lines appear here once the app, sync or a job writes to {{.LogDir}}.</span></div>
+    <script>
+        (function() {
+            var logEl = document.getElementById('log');
+            var statusEl = document.getElementById('status');
+            var followEl = document.getElementById('follow');
+            var timer = null;
+
+            function query() {
+                var params = new URLSearchParams();
+                var sources = [];
+                document.querySelectorAll('input[name=source]:checked').forEach(function(box) {
+                    sources.push(box.value);
+                });
+                params.set('source', sources.join(','));
+                params.set('level', document.getElementById('level').value);
+                params.set('q', document.getElementById('search').value);
+                params.set('lines', '500');
+                return params.toString();
+            }
+
+            function render(lines) {
+                var atBottom = logEl.scrollHeight - logEl.scrollTop - logEl.clientHeight < 30;
+                if (lines.length === 0) {
+                    logEl.innerHTML = '<span class="empty">No matching lines.</span>';
+                    return;
+                }
+                var html = lines.map(function(line) {
+                    var time = line.time ? new Date(line.time).toLocaleTimeString() : '';
+                    return '<div class="line-' + line.level + '">' +
+                        '<span class="line-time">' + time + '</span> ' +
+                        '<span class="line-source">' + line.source + '</span> ' +
+                        line.html + '</div>';
+                }).join('');
+                logEl.innerHTML = html;
+                if (atBottom || followEl.checked) {
+                    logEl.scrollTop = logEl.scrollHeight;
+                }
+            }
+
+            function load() {
+                var anySource = document.querySelector('input[name=source]:checked');
+                if (!anySource) {
+                    render([]);
+                    return;
+                }
+                fetch('api/logs?' + query(), {cache: 'no-store'})
+                    .then(function(response) {
+                        if (!response.ok) {
+                            throw new Error('HTTP ' + response.status);
+                        }
+                        return response.json();
+                    })
+                    .then(function(data) {
+                        render(data.lines);
+                        statusEl.textContent = data.lines.length + ' lines · ' + new Date().toLocaleTimeString();
+                    })
+                    .catch(function(err) {
+                        statusEl.textContent = 'Error loading logs: ' + err.message;
+                    });
+            }
+
+            function schedule() {
+                clearInterval(timer);
+                timer = followEl.checked ? setInterval(load, 2000) : null;
+            }
+
+            document.querySelectorAll('header input, header select').forEach(function(el) {
+                el.addEventListener(el.type === 'search' ? 'input' : 'change', load);
+            });
+            followEl.addEventListener('change', schedule);
+            load();
+            schedule();
+        })();
+    </script>
+</body>
+</html>
+`
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"log"
@@ -12,14 +13,22 @@ import (
 
 // WelcomePageData holds data for the welcome page template
 type WelcomePageData struct {
-	RepoURL          string
-	RepoFolder       string
-	RepoBranch       string
-	DevStartCommand  string
-	WorkspacePath    string
-	SyncInterval     string
-	EnableDevHealth  string
-	Timestamp        string
+	RepoURL         string
+	RepoFolder      string
+	RepoBranch      string
+	DevStartCommand string
+	WorkspacePath   string
+	SyncInterval    string
+	EnableDevHealth string
+	Timestamp       string
+	Diagnostics     DiagnosticsReport
+	AppSpec         AppSpecReport
+	ControlsEnabled bool
+	TerminalEnabled bool
+	ControlJobs     []string
+	Project         Project
+	ExamplesURL     string
+	DeployedCommit  *GitCommit
 }
 
 // welcomeHandler handles requests to the root path
@@ -30,16 +39,28 @@ func welcomeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Gather environment data
+	// Gather environment data; credentials are masked since the page is public
 	data := WelcomePageData{
-		RepoURL:         getEnvOrDefault("GITHUB_REPO_URL", "not set"),
-		RepoFolder:      getEnvOrDefault("GITHUB_REPO_FOLDER", "not set"),
-		RepoBranch:      getEnvOrDefault("GITHUB_BRANCH", "not set"),
-		DevStartCommand:  getEnvOrDefault("DEV_START_COMMAND", "not set"),
-		WorkspacePath:    getEnvOrDefault("WORKSPACE_PATH", "/workspaces/app"),
-		SyncInterval:     getEnvOrDefault("GITHUB_SYNC_INTERVAL", "30"),
-		EnableDevHealth:  getEnvOrDefault("ENABLE_DEV_HEALTH", "true"),
-		Timestamp:        time.Now().UTC().Format(time.RFC3339),
+		RepoURL:         displayEnv("GITHUB_REPO_URL", "not set"),
+		RepoFolder:      displayEnv("GITHUB_REPO_FOLDER", "not set"),
+		RepoBranch:      displayEnv("GITHUB_BRANCH", "not set"),
+		DevStartCommand: displayEnv("DEV_START_COMMAND", "not set"),
+		WorkspacePath:   displayEnv("WORKSPACE_PATH", "/workspaces/app"),
+		SyncInterval:    displayEnv("GITHUB_SYNC_INTERVAL", "30"),
+		EnableDevHealth: displayEnv("ENABLE_DEV_HEALTH", "true"),
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+		Diagnostics:     runDiagnostics(currentConfig()),
+		AppSpec:         lintAppSpec(currentConfig()),
+		ControlsEnabled: controlsEnabled(),
+		TerminalEnabled: terminalEnabled(),
+		Project:         detectProject(projectWorkspace()),
+		ExamplesURL:     appExamplesURL,
+		DeployedCommit:  currentGitRepo().head(),
+	}
+	for _, job := range controlJobs {
+		if os.Getenv(job+"_COMMAND") != "" {
+			data.ControlJobs = append(data.ControlJobs, job)
+		}
 	}
 
 	// Set content type header
@@ -54,6 +75,11 @@ func welcomeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// displayEnv returns an environment variable with credentials redacted
+func displayEnv(key, defaultValue string) string {
+	return redactValue(key, getEnvOrDefault(key, defaultValue))
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -61,20 +87,46 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Printf("Warning: Invalid %s value '%s', using default %d", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
 func main() {
 	// Get port from environment variable, default to 8080
-	port := 8080
-	if portStr := os.Getenv("WELCOME_PAGE_PORT"); portStr != "" {
-		if p, err := strconv.Atoi(portStr); err == nil {
-			port = p
-		} else {
-			log.Printf("Warning: Invalid WELCOME_PAGE_PORT value '%s', using default %d", portStr, port)
-		}
-	}
+	port := getEnvInt("WELCOME_PAGE_PORT", 8080)
 
 	// Create HTTP server
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", welcomeHandler)
+	mux.HandleFunc("/api/diagnostics", diagnosticsHandler)
+	mux.HandleFunc("/api/appspec", appSpecHandler)
+	mux.HandleFunc("/events", eventsHandler)
+	mux.HandleFunc("/logs", logsPageHandler)
+	mux.HandleFunc("/api/logs", logsAPIHandler)
+	mux.HandleFunc("/api/v1/status", statusHandler)
+	mux.HandleFunc("/api/project", projectHandler)
+	mux.HandleFunc("/dev_startup.sh", startupScriptHandler)
+	mux.HandleFunc("/git", gitPageHandler)
+	mux.HandleFunc("/api/git/commits", gitCommitsHandler)
+	mux.HandleFunc("/api/git/diff", gitDiffHandler)
+	mux.HandleFunc("/docs", docsRedirectHandler)
+	mux.HandleFunc("/docs/", docsHandler)
+	mux.HandleFunc("/api/control/sync", syncNowHandler)
+	mux.HandleFunc("/api/control/jobs/", jobRunHandler)
+	mux.HandleFunc("/terminal", terminalPageHandler)
+	mux.HandleFunc("/terminal/ws", terminalSocketHandler)
+	mux.HandleFunc("/terminal/assets/", terminalAssetHandler)
+	mux.HandleFunc("/api/terminal/sessions", terminalSessionsHandler)
+	mux.HandleFunc("/api/terminal/sessions/", terminalSessionHandler)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf("0.0.0.0:%d", port),
@@ -84,9 +136,43 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	go activity.Watch(context.Background())
+
+	// In proxy mode the server stays in front of the app instead of exiting
+	basePath := "/"
+	if getEnvOrDefault("ENABLE_WELCOME_PROXY", "false") == "true" {
+		appPort := getEnvInt("WELCOME_PROXY_APP_PORT", 3000)
+		crashAfter := time.Duration(getEnvInt("WELCOME_PROXY_CRASH_SECONDS", 60)) * time.Second
+		proxy := newAppProxy(appPort, crashAfter, mux)
+		go proxy.Watch(context.Background())
+		upstream = proxy
+
+		// Streaming responses and WebSockets outlive any fixed read/write timeout
+		server.Handler = proxy
+		server.ReadTimeout = 0
+		server.WriteTimeout = 0
+		server.ReadHeaderTimeout = 5 * time.Second
+		basePath = devPathPrefix + "/"
+		log.Printf("Proxy mode: forwarding to the app on port %d", appPort)
+	}
+
 	// Log server start
 	log.Printf("Welcome page server starting on port %d", port)
-	log.Printf("Welcome page: http://0.0.0.0:%d/", port)
+	log.Printf("Welcome page: http://0.0.0.0:%d%s", port, basePath)
+	log.Printf("Diagnostics: http://0.0.0.0:%d%sapi/diagnostics", port, basePath)
+	log.Printf("App spec check: http://0.0.0.0:%d%sapi/appspec", port, basePath)
+	log.Printf("Status API: http://0.0.0.0:%d%sapi/v1/status", port, basePath)
+	log.Printf("Live events: http://0.0.0.0:%d%sevents", port, basePath)
+	log.Printf("Logs: http://0.0.0.0:%d%slogs", port, basePath)
+	log.Printf("Commits: http://0.0.0.0:%d%sgit", port, basePath)
+	log.Printf("Docs: http://0.0.0.0:%d%sdocs/", port, basePath)
+	log.Printf("Suggested dev_startup.sh: http://0.0.0.0:%d%sdev_startup.sh", port, basePath)
+	if controlsEnabled() {
+		log.Printf("Controls: POST http://0.0.0.0:%d%sapi/control/sync and %sapi/control/jobs/{PRE_DEPLOY,POST_DEPLOY}", port, basePath, basePath)
+	}
+	if terminalEnabled() {
+		log.Printf("Web terminal: http://0.0.0.0:%d%sterminal", port, basePath)
+	}
 
 	// Start server
 	if err := server.ListenAndServe(); err != nil {
@@ -258,6 +344,108 @@ const welcomePageHTML = `<!DOCTYPE html>
             font-size: 0.85em;
             margin-left: 8px;
         }
+        .finding {
+            padding: 12px 15px;
+            margin: 10px 0;
+            border-radius: 4px;
+        }
+        .finding-error {
+            background: #f8d7da;
+            border-left: 4px solid #dc3545;
+        }
+        .finding-warning {
+            background: #fff3cd;
+            border-left: 4px solid #ffc107;
+        }
+        .finding-info {
+            background: #e7f1ff;
+            border-left: 4px solid #667eea;
+        }
+        .finding .badge {
+            margin-left: 0;
+            margin-right: 8px;
+            text-transform: uppercase;
+        }
+        .finding-hint {
+            margin-top: 6px;
+            font-size: 0.9em;
+            color: #555;
+        }
+        .activity-feed {
+            list-style: none;
+            max-height: 260px;
+            overflow-y: auto;
+            background: #f8f9fa;
+            border-radius: 4px;
+            padding: 5px 15px;
+        }
+        .activity-feed li {
+            padding: 6px 0 6px 10px;
+            border-left: 3px solid #667eea;
+            margin: 6px 0;
+            font-size: 0.95em;
+        }
+        .activity-feed li.activity-success {
+            border-left-color: #28a745;
+        }
+        .activity-feed li.activity-error {
+            border-left-color: #dc3545;
+        }
+        .activity-feed li.activity-empty {
+            border-left-color: transparent;
+            color: #888;
+            font-style: italic;
+        }
+        .activity-time {
+            color: #888;
+            font-family: 'Courier New', monospace;
+            font-size: 0.85em;
+            margin-right: 8px;
+        }
+        .control-buttons {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 10px;
+            margin: 12px 0;
+        }
+        .control-buttons input {
+            flex: 1;
+            min-width: 180px;
+            padding: 8px 10px;
+            border: 1px solid #ccc;
+            border-radius: 4px;
+        }
+        .control-buttons button, .control-buttons a {
+            background: #667eea;
+            color: white;
+            border: none;
+            border-radius: 4px;
+            padding: 8px 16px;
+            font-weight: 600;
+            font-size: 0.9em;
+            text-decoration: none;
+            cursor: pointer;
+        }
+        .control-buttons button:disabled {
+            background: #aaa;
+            cursor: wait;
+        }
+        .control-output {
+            background: #2d2d2d;
+            color: #f8f8f2;
+            padding: 15px;
+            border-radius: 4px;
+            font-family: 'Courier New', monospace;
+            font-size: 0.85em;
+            max-height: 320px;
+            overflow: auto;
+            white-space: pre-wrap;
+        }
+        details summary {
+            cursor: pointer;
+            font-weight: 600;
+            margin: 10px 0;
+        }
         .ai-section {
             background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
             color: white;
@@ -319,6 +507,75 @@ const welcomePageHTML = `<!DOCTYPE html>
                 <span class="status-label">Dev Health Server:</span>
                 <span class="status-value">{{.EnableDevHealth}}</span>
             </div>
+            <div class="status-item">
+                <span class="status-label">Deployed Commit:</span>
+                {{if .DeployedCommit}}<span class="status-value">{{.DeployedCommit.ShortSHA}} {{.DeployedCommit.Subject}}</span>
+                <span class="hint-text">(<a href="git">commits and changes</a>)</span>{{else}}<span class="status-value">not synced yet</span>{{end}}
+            </div>
+        </div>
+
+        <div class="section">
+            <h2>📡 Live Activity <span class="badge badge-warning" id="activity-status">Connecting</span></h2>
+            <ul class="activity-feed" id="activity-feed">
+                <li class="activity-empty">Waiting for activity. New commits, deploy jobs and app restarts show up here as they happen.</li>
+            </ul>
+            <p style="margin-top: 8px;"><span class="hint-text">Full app, sync and job output in the <a href="logs">log viewer</a></span></p>
+        </div>
+
+        <div class="section">
+            <h2>🎛️ Controls</h2>
+            {{if .ControlsEnabled}}
+            <p>Sync the latest commit now instead of waiting for the next interval, or rerun a deploy job against the current checkout.</p>
+            <div class="control-buttons">
+                <input type="password" id="control-token" placeholder="WELCOME_CONTROL_TOKEN" autocomplete="off">
+                <button type="button" data-control="api/control/sync">Sync now</button>
+                {{range .ControlJobs}}<button type="button" data-control="api/control/jobs/{{.}}">Rerun {{.}}</button>
+                {{end}}
+            </div>
+            <pre class="control-output" id="control-output" hidden></pre>
+            {{if .TerminalEnabled}}<p style="margin-top: 8px;"><a href="terminal">💻 Open the web terminal</a> <span class="hint-text">(a shell in the container, with the same token)</span></p>{{end}}
+            {{else}}
+            <p>Set <code>WELCOME_CONTROL_TOKEN</code> (as a secret) to enable buttons that sync immediately and rerun PRE_DEPLOY / POST_DEPLOY jobs.</p>
+            {{end}}
+        </div>
+
+        <div class="section">
+            <h2>🩺 Configuration Check</h2>
+            {{if .Diagnostics.Findings}}
+            {{range .Diagnostics.Findings}}
+            <div class="finding finding-{{.Severity}}">
+                <span class="badge {{if eq .Severity "error"}}badge-danger{{else if eq .Severity "warning"}}badge-warning{{else}}badge-success{{end}}">{{.Severity}}</span>
+                {{.Message}}
+                <div class="finding-hint">💡 {{.Hint}}</div>
+            </div>
+            {{end}}
+            {{else}}
+            <div class="success">
+                <strong>✓ No configuration problems found</strong>
+            </div>
+            {{end}}
+            <p style="margin-top: 8px;"><span class="hint-text">Also available as JSON at <a href="api/diagnostics">api/diagnostics</a></span></p>
+        </div>
+
+        <div class="section">
+            <h2>📋 App Spec Check</h2>
+            {{if .AppSpec.Error}}
+            <p>{{.AppSpec.Error}}</p>
+            {{else if .AppSpec.Findings}}
+            <p>Checked <code>{{.AppSpec.File}}</code> against how startup.sh and the Dockerfile read each setting:</p>
+            {{range .AppSpec.Findings}}
+            <div class="finding finding-{{.Severity}}">
+                <span class="badge {{if eq .Severity "error"}}badge-danger{{else if eq .Severity "warning"}}badge-warning{{else}}badge-success{{end}}">{{.Severity}}</span>
+                <code>{{$.AppSpec.File}}:{{.Line}}</code> {{.Message}}
+                <div class="finding-hint">💡 {{.Hint}}</div>
+            </div>
+            {{end}}
+            {{else}}
+            <div class="success">
+                <strong>✓ No problems found in {{.AppSpec.File}}</strong>
+            </div>
+            {{end}}
+            <p style="margin-top: 8px;"><span class="hint-text">Also available as JSON at <a href="api/appspec">api/appspec</a>, and from <code>appspec-lint</code> in the container</span></p>
         </div>
 
         {{if eq .RepoURL "not set"}}
@@ -367,7 +624,7 @@ const welcomePageHTML = `<!DOCTYPE html>
                 <div class="code-block">
                     <code>#!/bin/bash<br>cd /workspaces/app<br>npm install<br>npm run dev -- --hostname 0.0.0.0 --port 8080</code>
                 </div>
-                <p style="margin-top: 10px;">See ready-made templates in <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow/tree/main/hot-reload-template/examples" target="_blank">hot-reload-template/examples</a>, and ask your AI assistant to tailor a dev_startup.sh for your specific codebase.</p>
+                <p style="margin-top: 10px;">See ready-made templates in <a href="docs/app-examples/README.md">app-examples</a>, and ask your AI assistant to tailor a dev_startup.sh for your specific codebase.</p>
             </div>
 
             <div class="step">
@@ -401,7 +658,7 @@ const welcomePageHTML = `<!DOCTYPE html>
                 <li>Execute commands in your running container for debugging</li>
             </ul>
             <p style="margin-top: 15px;"><strong>Supported AI assistants:</strong> Claude Code, GitHub Copilot, Cursor, Codex, Antigravity, or any agent that can execute commands</p>
-            <p><strong>Get started:</strong> See the <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow/blob/main/hot-reload-template/agent.md" target="_blank">agent.md playbook</a> for detailed automation instructions.</p>
+            <p><strong>Get started:</strong> See the <a href="docs/agent.md">agent.md playbook</a> for detailed automation instructions.</p>
         </div>
         {{else}}
         <div class="success">
@@ -416,31 +673,24 @@ const welcomePageHTML = `<!DOCTYPE html>
         {{end}}
 
         <div class="section">
-            <h2>📚 Example dev_startup.sh Scripts</h2>
-            
-            <div class="step">
-                <strong>Next.js / React</strong>
-                <div class="code-block">
-                    <code>#!/bin/bash<br>cd /workspaces/app<br>npm install<br>npm run dev -- --hostname 0.0.0.0 --port 8080</code>
-                </div>
-                <p style="margin-top: 8px;">Starting point only—use your AI assistant to generate a dev_startup.sh tailored to your project, or copy from the <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow/tree/main/hot-reload-template/examples" target="_blank">examples directory</a>.</p>
-            </div>
-
-            <div class="step">
-                <strong>Python FastAPI</strong>
-                <div class="code-block">
-                    <code>#!/bin/bash<br>cd /workspaces/app<br>uv sync --no-dev<br>uv run uvicorn main:app --host 0.0.0.0 --port 8080 --reload</code>
-                </div>
-                <p style="margin-top: 8px;">Adapt with your AI assistant or reuse the <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow/tree/main/hot-reload-template/examples" target="_blank">examples/dev_startup.sh.python</a> template.</p>
-            </div>
-
-            <div class="step">
-                <strong>Go</strong>
-                <div class="code-block">
-                    <code>#!/bin/bash<br>cd /workspaces/app<br>go mod tidy<br>go run main.go</code>
+            <h2>🪄 Suggested dev_startup.sh</h2>
+            {{if .Project.Detected}}
+            <p>Detected a <strong>{{.Project.Label}}</strong> project from {{range $i, $file := .Project.Evidence}}{{if $i}}, {{end}}<code>{{$file}}</code>{{end}}{{if .Project.PackageManager}}, using {{.Project.PackageManager}}{{end}}. This script is modeled on <a href="{{.ExamplesURL}}/{{.Project.Example}}" target="_blank">app-examples/{{.Project.Example}}</a>: it installs dependencies, handles lock file merge conflicts, reinstalls when they change and binds to <code>0.0.0.0:$PORT</code>.</p>
+            {{range .Project.Notes}}<p class="finding-hint">💡 {{.}}</p>
+            {{end}}
+            <details{{if not .Project.HasStartupScript}} open{{end}}>
+                <summary>{{if .Project.HasStartupScript}}Your repository already has a dev_startup.sh; show the suggestion for comparison{{else}}dev_startup.sh{{end}}</summary>
+                <div class="control-buttons">
+                    <button type="button" id="copy-startup-script">Copy</button>
+                    <a href="dev_startup.sh" download>Download</a>
                 </div>
-                <p style="margin-top: 8px;">Have your AI assistant extend this for your modules, or copy from <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow/tree/main/hot-reload-template/examples" target="_blank">examples/dev_startup.sh.golang</a> and adjust.</p>
-            </div>
+                <pre class="control-output" id="startup-script">{{.Project.Script}}</pre>
+            </details>
+            <p style="margin-top: 10px;">Commit it as <code>dev_startup.sh</code> at the root of your app{{if ne .RepoFolder "not set"}} (<code>{{.RepoFolder}}</code>){{end}} and set <code>DEV_START_COMMAND</code> to <code>bash dev_startup.sh</code>.</p>
+            {{else}}
+            <p>Once your repository is synced, this section suggests a <code>dev_startup.sh</code> for it. Go (<code>go.mod</code>), Node.js (<code>package.json</code>, including Next.js, Nuxt, Astro and Vite), Python (<code>pyproject.toml</code> / <code>uv.lock</code>), Ruby on Rails (<code>Gemfile</code>) and Rust (<code>Cargo.toml</code>) projects are recognized.</p>
+            <p style="margin-top: 8px;">Until then, see the ready-made scripts in <a href="{{.ExamplesURL}}" target="_blank">app-examples</a>.</p>
+            {{end}}
         </div>
 
         <div class="section">
@@ -463,9 +713,120 @@ const welcomePageHTML = `<!DOCTYPE html>
         </div>
 
         <div class="footer">
-            <p>Container started at: {{.Timestamp}}</p>
-            <p>For more information, see the <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow" target="_blank">template repository</a></p>
+            <p>Page loaded at: {{.Timestamp}}</p>
+            <p>For more information, see the <a href="docs/">bundled documentation</a> or the <a href="https://github.com/bikram20/do-app-platform-ai-dev-workflow" target="_blank">template repository</a></p>
         </div>
     </div>
+    <script>
+        (function() {
+            var feed = document.getElementById('activity-feed');
+            var status = document.getElementById('activity-status');
+            if (!window.EventSource) {
+                status.textContent = 'Unsupported';
+                return;
+            }
+            var source = new EventSource('events');
+            source.onopen = function() {
+                status.textContent = 'Live';
+                status.className = 'badge badge-success';
+            };
+            source.onerror = function() {
+                status.textContent = 'Reconnecting';
+                status.className = 'badge badge-warning';
+            };
+            source.onmessage = function(message) {
+                var event = JSON.parse(message.data);
+                var empty = feed.querySelector('.activity-empty');
+                if (empty) {
+                    feed.removeChild(empty);
+                }
+                var item = document.createElement('li');
+                item.className = 'activity-' + event.level;
+                var time = document.createElement('span');
+                time.className = 'activity-time';
+                time.textContent = new Date(event.time).toLocaleTimeString();
+                item.appendChild(time);
+                item.appendChild(document.createTextNode(event.message));
+                feed.insertBefore(item, feed.firstChild);
+            };
+        })();
+        (function() {
+            var button = document.getElementById('copy-startup-script');
+            if (!button) {
+                return;
+            }
+            var script = document.getElementById('startup-script');
+            function copied() {
+                button.textContent = 'Copied!';
+                setTimeout(function() {
+                    button.textContent = 'Copy';
+                }, 2000);
+            }
+            button.addEventListener('click', function() {
+                if (navigator.clipboard && window.isSecureContext) {
+                    navigator.clipboard.writeText(script.textContent).then(copied);
+                    return;
+                }
+                // The clipboard API needs HTTPS; select the text and fall back to execCommand
+                var range = document.createRange();
+                range.selectNodeContents(script);
+                var selection = window.getSelection();
+                selection.removeAllRanges();
+                selection.addRange(range);
+                if (document.execCommand('copy')) {
+                    copied();
+                }
+            });
+        })();
+        (function() {
+            var tokenInput = document.getElementById('control-token');
+            if (!tokenInput) {
+                return;
+            }
+            var output = document.getElementById('control-output');
+            var buttons = document.querySelectorAll('[data-control]');
+            tokenInput.value = localStorage.getItem('welcomeControlToken') || '';
+            tokenInput.addEventListener('change', function() {
+                localStorage.setItem('welcomeControlToken', tokenInput.value);
+            });
+            function setBusy(busy) {
+                for (var i = 0; i < buttons.length; i++) {
+                    buttons[i].disabled = busy;
+                }
+            }
+            function run(url) {
+                setBusy(true);
+                output.hidden = false;
+                output.textContent = '';
+                fetch(url, {method: 'POST', headers: {'Authorization': 'Bearer ' + tokenInput.value}})
+                    .then(function(response) {
+                        var reader = response.body.getReader();
+                        var decoder = new TextDecoder();
+                        function read() {
+                            return reader.read().then(function(chunk) {
+                                if (chunk.done) {
+                                    return;
+                                }
+                                output.textContent += decoder.decode(chunk.value, {stream: true});
+                                output.scrollTop = output.scrollHeight;
+                                return read();
+                            });
+                        }
+                        return read();
+                    })
+                    .catch(function(err) {
+                        output.textContent += '\nRequest failed: ' + err;
+                    })
+                    .then(function() {
+                        setBusy(false);
+                    });
+            }
+            for (var i = 0; i < buttons.length; i++) {
+                buttons[i].addEventListener('click', function(e) {
+                    run(e.target.getAttribute('data-control'));
+                });
+            }
+        })();
+    </script>
 </body>
 </html>`
@@ -0,0 +1,719 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The docs renderer covers the GitHub-flavored Markdown the bundled docs use:
+// ATX and setext headings, paragraphs, nested lists and task lists, fenced
+// code, block quotes, tables, rules, and inline code, emphasis, links,
+// images and bare URLs. Raw HTML is escaped rather than passed through.
+
+var (
+	atxHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	fencePattern      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	rulePattern       = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	listItemPattern   = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])( {1,4}|\t|$)`)
+	tableDelimPattern = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	setextPattern     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	taskPattern       = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	bareURLPattern    = regexp.MustCompile(`https?://[^\s<>"]*[^\s<>".,:;!?'")\]]`)
+	autolinkPattern   = regexp.MustCompile(`^<(https?://[^\s<>]+)>`)
+)
+
+// mdHeading is a rendered heading, for the table of contents and search
+type mdHeading struct {
+	Level int
+	Text  string
+	ID    string
+}
+
+// mdSection is the plain text under a heading, for search
+type mdSection struct {
+	Heading mdHeading
+	Text    string
+}
+
+// mdRenderer turns one Markdown document into HTML
+type mdRenderer struct {
+	// resolveLink rewrites link and image destinations
+	resolveLink func(dest string) string
+
+	out      strings.Builder
+	inLink   bool
+	headings []mdHeading
+	sections []mdSection
+	ids      map[string]int
+}
+
+// renderMarkdown renders src and returns its headings and per-section text
+func renderMarkdown(src string, resolveLink func(string) string) (string, []mdHeading, []mdSection) {
+	r := &mdRenderer{resolveLink: resolveLink, ids: make(map[string]int)}
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	r.sections = []mdSection{{}}
+	r.renderBlocks(strings.Split(src, "\n"), false, true)
+	if len(r.sections) > 1 && r.sections[0].Text == "" {
+		// Nothing before the first heading
+		r.sections = r.sections[1:]
+	}
+	return r.out.String(), r.headings, r.sections
+}
+
+// renderBlocks renders a sequence of block-level lines. tight lists render
+// paragraphs without <p>; top marks the document level, where headings start
+// search sections.
+func (r *mdRenderer) renderBlocks(lines []string, tight, top bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			i = r.renderFence(lines, i, top)
+
+		case atxHeadingPattern.MatchString(line):
+			m := atxHeadingPattern.FindStringSubmatch(line)
+			r.renderHeading(len(m[1]), m[2], top)
+			i++
+
+		case rulePattern.MatchString(line):
+			r.out.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[i], " "), ">"); i++ {
+				text := strings.TrimPrefix(strings.TrimLeft(lines[i], " "), ">")
+				quoted = append(quoted, strings.TrimPrefix(text, " "))
+			}
+			start := r.out.Len()
+			r.out.WriteString("<blockquote>\n")
+			r.renderBlocks(quoted, false, false)
+			r.out.WriteString("</blockquote>\n")
+			r.addRendered(top, start)
+
+		case listItemPattern.MatchString(line):
+			start := r.out.Len()
+			i = r.renderList(lines, i)
+			r.addRendered(top, start)
+
+		case i+1 < len(lines) && strings.Contains(line, "|") && tableDelimPattern.MatchString(lines[i+1]):
+			start := r.out.Len()
+			i = r.renderTable(lines, i)
+			r.addRendered(top, start)
+
+		default:
+			i = r.renderParagraph(lines, i, tight, top)
+		}
+	}
+}
+
+// renderFence renders a fenced code block starting at lines[start]
+func (r *mdRenderer) renderFence(lines []string, start int, top bool) int {
+	m := fencePattern.FindStringSubmatch(lines[start])
+	indent, fence := len(m[1]), m[2]
+	language, _, _ := strings.Cut(strings.TrimSpace(m[3]), " ")
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence[:1]) && len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		// Remove the opening fence's indentation from each line
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+
+	if language != "" {
+		fmt.Fprintf(&r.out, `<pre><code class="language-%s">`, html.EscapeString(language))
+	} else {
+		r.out.WriteString("<pre><code>")
+	}
+	text := strings.Join(code, "\n")
+	if len(code) > 0 {
+		text += "\n"
+	}
+	r.out.WriteString(html.EscapeString(text))
+	r.out.WriteString("</code></pre>\n")
+	r.addText(top, text)
+	return i
+}
+
+// renderHeading renders a heading with a GitHub-compatible anchor, so links
+// like CUSTOMIZATION.md#health-check-configuration keep working
+func (r *mdRenderer) renderHeading(level int, text string, top bool) {
+	inner := r.inline(text)
+	plain := plainText(inner)
+	id := r.uniqueID(slugify(plain))
+	heading := mdHeading{Level: level, Text: plain, ID: id}
+	r.headings = append(r.headings, heading)
+	if top {
+		r.sections = append(r.sections, mdSection{Heading: heading})
+	}
+	fmt.Fprintf(&r.out, `<h%d id="%s"><a class="anchor" href="#%s">#</a>%s</h%d>`+"\n", level, id, id, inner, level)
+}
+
+// renderList renders a bullet or ordered list starting at lines[start]
+func (r *mdRenderer) renderList(lines []string, start int) int {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	bullet := !unicode.IsDigit(rune(first[2][0]))
+	delimiter := first[2][len(first[2])-1:]
+
+	type item struct {
+		lines []string
+	}
+	var items []item
+	loose := false
+	blank := false
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		m := listItemPattern.FindStringSubmatch(line)
+		if m != nil && len(m[1]) <= len(first[1])+1 && sameListKind(m[2], bullet, delimiter) && !rulePattern.MatchString(line) {
+			if blank && len(items) > 0 {
+				loose = true
+			}
+			contentIndent := len(m[0])
+			if m[3] == "" || len(m[3]) > 4 {
+				contentIndent = len(m[1]) + len(m[2]) + 1
+			}
+			content := ""
+			if len(line) > contentIndent {
+				content = line[contentIndent:]
+			}
+			items = append(items, item{lines: []string{content}})
+			blank = false
+			i++
+
+			// Continuation lines belong to the item while indented past its marker
+			for i < len(lines) {
+				next := lines[i]
+				if strings.TrimSpace(next) == "" {
+					items[len(items)-1].lines = append(items[len(items)-1].lines, "")
+					blank = true
+					i++
+					continue
+				}
+				indent := len(next) - len(strings.TrimLeft(next, " "))
+				if indent >= contentIndent {
+					items[len(items)-1].lines = append(items[len(items)-1].lines, next[contentIndent:])
+					blank = false
+					i++
+					continue
+				}
+				// Lazy continuation of the item's paragraph
+				if !blank && !startsBlock(next) {
+					items[len(items)-1].lines = append(items[len(items)-1].lines, strings.TrimLeft(next, " "))
+					i++
+					continue
+				}
+				break
+			}
+			continue
+		}
+		break
+	}
+
+	if bullet {
+		r.out.WriteString("<ul>\n")
+	} else if number, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); number != 1 {
+		fmt.Fprintf(&r.out, "<ol start=\"%d\">\n", number)
+	} else {
+		r.out.WriteString("<ol>\n")
+	}
+	for _, it := range items {
+		content := it.lines
+		for len(content) > 0 && strings.TrimSpace(content[len(content)-1]) == "" {
+			content = content[:len(content)-1]
+		}
+		if len(content) > 0 {
+			if m := taskPattern.FindStringSubmatch(content[0]); m != nil {
+				checked := ""
+				if m[1] != " " {
+					checked = " checked"
+				}
+				fmt.Fprintf(&r.out, `<li class="task"><input type="checkbox" disabled%s> `, checked)
+				content = append([]string{content[0][len(m[0]):]}, content[1:]...)
+			} else {
+				r.out.WriteString("<li>")
+			}
+		} else {
+			r.out.WriteString("<li>")
+		}
+		r.renderBlocks(content, !loose, false)
+		r.out.WriteString("</li>\n")
+	}
+	if bullet {
+		r.out.WriteString("</ul>\n")
+	} else {
+		r.out.WriteString("</ol>\n")
+	}
+	return i
+}
+
+// sameListKind reports whether a list marker continues the current list
+func sameListKind(marker string, bullet bool, delimiter string) bool {
+	isBullet := !unicode.IsDigit(rune(marker[0]))
+	if isBullet != bullet {
+		return false
+	}
+	return isBullet || strings.HasSuffix(marker, delimiter)
+}
+
+// startsBlock reports whether line starts a block that interrupts a paragraph
+func startsBlock(line string) bool {
+	return atxHeadingPattern.MatchString(line) ||
+		fencePattern.MatchString(line) ||
+		rulePattern.MatchString(line) ||
+		listItemPattern.MatchString(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// renderTable renders a GitHub table whose header is lines[start]
+func (r *mdRenderer) renderTable(lines []string, start int) int {
+	header := splitTableRow(lines[start])
+	var align []string
+	for _, cell := range splitTableRow(lines[start+1]) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			align = append(align, "center")
+		case strings.HasSuffix(cell, ":"):
+			align = append(align, "right")
+		case strings.HasPrefix(cell, ":"):
+			align = append(align, "left")
+		default:
+			align = append(align, "")
+		}
+	}
+	cellTag := func(tag string, column int, text string) {
+		if column < len(align) && align[column] != "" {
+			fmt.Fprintf(&r.out, `<%s style="text-align: %s">`, tag, align[column])
+		} else {
+			fmt.Fprintf(&r.out, "<%s>", tag)
+		}
+		r.out.WriteString(r.inline(text))
+		fmt.Fprintf(&r.out, "</%s>", tag)
+	}
+
+	r.out.WriteString("<table>\n<thead><tr>")
+	for column, cell := range header {
+		cellTag("th", column, cell)
+	}
+	r.out.WriteString("</tr></thead>\n<tbody>\n")
+	i := start + 2
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|"); i++ {
+		cells := splitTableRow(lines[i])
+		r.out.WriteString("<tr>")
+		for column := range header {
+			cell := ""
+			if column < len(cells) {
+				cell = cells[column]
+			}
+			cellTag("td", column, cell)
+		}
+		r.out.WriteString("</tr>\n")
+	}
+	r.out.WriteString("</tbody>\n</table>\n")
+	return i
+}
+
+// splitTableRow splits a table row on unescaped pipes outside code spans
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	inCode := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			inCode = !inCode
+			cell.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// renderParagraph renders a paragraph, or a setext heading when the lines are
+// underlined with = or -
+func (r *mdRenderer) renderParagraph(lines []string, start int, tight, top bool) int {
+	var text []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if len(text) > 0 {
+			if m := setextPattern.FindStringSubmatch(line); m != nil {
+				level := 2
+				if m[1][0] == '=' {
+					level = 1
+				}
+				r.renderHeading(level, strings.Join(text, " "), top)
+				return i + 1
+			}
+			if startsBlock(line) {
+				break
+			}
+		}
+		text = append(text, line)
+	}
+
+	inner := r.inlineLines(text)
+	if tight {
+		r.out.WriteString(inner + "\n")
+	} else {
+		r.out.WriteString("<p>" + inner + "</p>\n")
+	}
+	r.addText(top, plainText(inner))
+	return i
+}
+
+// inlineLines renders paragraph lines, honouring hard line breaks
+func (r *mdRenderer) inlineLines(lines []string) string {
+	var out strings.Builder
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " \t")
+		hardBreak := false
+		if i < len(lines)-1 {
+			if strings.HasSuffix(line, "  ") {
+				hardBreak = true
+			} else if strings.HasSuffix(line, `\`) {
+				hardBreak = true
+				line = strings.TrimSuffix(line, `\`)
+			}
+		}
+		out.WriteString(r.inline(strings.TrimRight(line, " \t")))
+		if i < len(lines)-1 {
+			if hardBreak {
+				out.WriteString("<br>")
+			}
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+// addText appends plain text to the current search section
+func (r *mdRenderer) addText(top bool, text string) {
+	if !top {
+		return
+	}
+	section := &r.sections[len(r.sections)-1]
+	section.Text = strings.TrimSpace(section.Text + " " + strings.Join(strings.Fields(text), " "))
+}
+
+// cellBreaks keeps table cells and list items apart in search text
+var cellBreaks = strings.NewReplacer("</th>", " ", "</td>", " ", "</li>", " ")
+
+// addRendered adds the text of everything rendered since start to the
+// current search section
+func (r *mdRenderer) addRendered(top bool, start int) {
+	if top {
+		rendered := cellBreaks.Replace(r.out.String()[start:])
+		r.addText(true, plainText(rendered))
+	}
+}
+
+// uniqueID numbers repeated heading anchors the way GitHub does: x, x-1, x-2
+func (r *mdRenderer) uniqueID(id string) string {
+	count := r.ids[id]
+	r.ids[id] = count + 1
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", id, count)
+	}
+	return id
+}
+
+// inline renders emphasis, code spans, links, images and bare URLs
+func (r *mdRenderer) inline(text string) string {
+	var out strings.Builder
+	plain := 0 // start of the text not yet written
+	flush := func(end int) {
+		if r.inLink {
+			// Links cannot nest, so bare URLs in a link label stay text
+			out.WriteString(html.EscapeString(text[plain:end]))
+		} else {
+			out.WriteString(linkifyText(text[plain:end]))
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.ContainsRune("\\`*_{}[]()#+-.!|<>~", rune(text[i+1])):
+			flush(i)
+			out.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			plain = i
+			continue
+
+		case c == '`':
+			run := countRun(text, i, '`')
+			if end := findCodeEnd(text, i+run, run); end >= 0 {
+				flush(i)
+				code := text[i+run : end]
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				out.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = end + run
+				plain = i
+				continue
+			}
+			i += run
+			continue
+
+		case c == '!' && i+1 < len(text) && text[i+1] == '[':
+			if label, dest, end, ok := parseLink(text, i+1); ok {
+				flush(i)
+				fmt.Fprintf(&out, `<img src="%s" alt="%s">`, html.EscapeString(r.resolve(dest)), html.EscapeString(plainText(r.inline(label))))
+				i = end
+				plain = i
+				continue
+			}
+
+		case c == '[':
+			if label, dest, end, ok := parseLink(text, i); ok {
+				flush(i)
+				href := r.resolve(dest)
+				target := ""
+				if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+					target = ` target="_blank" rel="noopener"`
+				}
+				inLink := r.inLink
+				r.inLink = true
+				fmt.Fprintf(&out, `<a href="%s"%s>%s</a>`, html.EscapeString(href), target, r.inline(label))
+				r.inLink = inLink
+				i = end
+				plain = i
+				continue
+			}
+
+		case c == '<':
+			if m := autolinkPattern.FindStringSubmatch(text[i:]); m != nil && !r.inLink {
+				flush(i)
+				url := html.EscapeString(m[1])
+				fmt.Fprintf(&out, `<a href="%s" target="_blank" rel="noopener">%s</a>`, url, url)
+				i += len(m[0])
+				plain = i
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			run := countRun(text, i, c)
+			if c == '~' && run != 2 {
+				i += run
+				continue
+			}
+			// Underscores only emphasize at word boundaries, so snake_case stays intact
+			if c == '_' && i > 0 && isWordByte(text[i-1]) {
+				i += run
+				continue
+			}
+			width := min(run, 2)
+			if end := findEmphasisEnd(text, i+width, c, width); end >= 0 {
+				flush(i)
+				tag := map[int]string{1: "em", 2: "strong"}[width]
+				if c == '~' {
+					tag = "del"
+				}
+				fmt.Fprintf(&out, "<%s>%s</%s>", tag, r.inline(text[i+width:end]), tag)
+				i = end + width
+				plain = i
+				continue
+			}
+			i += run
+			continue
+		}
+		i++
+	}
+	flush(len(text))
+	return out.String()
+}
+
+// resolve rewrites a link destination and drops unsafe schemes
+func (r *mdRenderer) resolve(dest string) string {
+	lower := strings.ToLower(dest)
+	if scheme, _, ok := strings.Cut(lower, ":"); ok && !strings.ContainsAny(scheme, "/?#") {
+		if scheme != "http" && scheme != "https" && scheme != "mailto" {
+			return "#"
+		}
+		return dest
+	}
+	if r.resolveLink != nil {
+		return r.resolveLink(dest)
+	}
+	return dest
+}
+
+// linkifyText escapes text and turns bare URLs into links
+func linkifyText(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range bareURLPattern.FindAllStringIndex(text, -1) {
+		out.WriteString(html.EscapeString(text[last:loc[0]]))
+		url := html.EscapeString(text[loc[0]:loc[1]])
+		fmt.Fprintf(&out, `<a href="%s" target="_blank" rel="noopener">%s</a>`, url, url)
+		last = loc[1]
+	}
+	out.WriteString(html.EscapeString(text[last:]))
+	return out.String()
+}
+
+// parseLink parses "[label](dest "title")" starting at the "[" at start
+func parseLink(text string, start int) (label, dest string, end int, ok bool) {
+	depth := 0
+	close := -1
+	for i := start; i < len(text) && close < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '`':
+			// Brackets inside code spans do not count
+			run := countRun(text, i, '`')
+			if codeEnd := findCodeEnd(text, i+run, run); codeEnd >= 0 {
+				i = codeEnd + run - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				close = i
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(text) || text[close+1] != '(' {
+		return "", "", 0, false
+	}
+
+	depth = 0
+	for i := close + 1; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				dest = strings.TrimSpace(text[close+2 : i])
+				// Drop an optional "title"
+				if space := strings.IndexAny(dest, " \t"); space >= 0 {
+					dest = dest[:space]
+				}
+				dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+				return text[start+1 : close], dest, i + 1, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// countRun counts consecutive c bytes starting at i
+func countRun(text string, i int, c byte) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// findCodeEnd finds a backtick run of exactly length run at or after from
+func findCodeEnd(text string, from, run int) int {
+	for i := from; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		n := countRun(text, i, '`')
+		if n == run {
+			return i
+		}
+		i += n
+	}
+	return -1
+}
+
+// findEmphasisEnd finds the closing delimiter for emphasis opened before from.
+// The opener must be followed, and the closer preceded, by non-space.
+func findEmphasisEnd(text string, from int, c byte, width int) int {
+	if from >= len(text) || text[from] == ' ' {
+		return -1
+	}
+	for i := from + 1; i+width <= len(text); i++ {
+		if text[i] == '`' {
+			run := countRun(text, i, '`')
+			if end := findCodeEnd(text, i+run, run); end >= 0 {
+				i = end + run - 1
+			}
+			continue
+		}
+		if text[i] != c || text[i-1] == ' ' {
+			continue
+		}
+		n := countRun(text, i, c)
+		if n < width {
+			i += n - 1
+			continue
+		}
+		// A closing underscore must end a word
+		if c == '_' && i+n < len(text) && isWordByte(text[i+n]) {
+			i += n - 1
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// tagPattern matches HTML tags, for plainText
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// plainText strips tags from rendered inline HTML
+func plainText(rendered string) string {
+	return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(rendered, "")))
+}
+
+// slugify builds a heading anchor like GitHub: lowercase, punctuation and
+// emoji removed, spaces turned into hyphens
+func slugify(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraph", "Hello *world*", "<p>Hello <em>world</em></p>\n"},
+		{"strong and code", "**bold** and `a < b`", "<p><strong>bold</strong> and <code>a &lt; b</code></p>\n"},
+		{"escaped html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"underscore inside word", "dev_startup_sh", "<p>dev_startup_sh</p>\n"},
+		{"fenced code", "```bash\necho <hi>\n```", "<pre><code class=\"language-bash\">echo &lt;hi&gt;\n</code></pre>\n"},
+		{"relative link", "[Jobs](docs/JOBS.md#hooks)", "<p><a href=\"docs/JOBS.md#hooks\">Jobs</a></p>\n"},
+		{"external link", "[DO](https://www.digitalocean.com)", "<p><a href=\"https://www.digitalocean.com\" target=\"_blank\" rel=\"noopener\">DO</a></p>\n"},
+		{"javascript link neutralized", "[x](javascript:alert(1))", "<p><a href=\"#\">x</a></p>\n"},
+		{"bare url", "See https://example.com.", "<p>See <a href=\"https://example.com\" target=\"_blank\" rel=\"noopener\">https://example.com</a>.</p>\n"},
+		{"tight list", "- one\n- two", "<ul>\n<li>one\n</li>\n<li>two\n</li>\n</ul>\n"},
+		{"ordered start", "3. three\n4. four", "<ol start=\"3\">\n<li>three\n</li>\n<li>four\n</li>\n</ol>\n"},
+		{"task list", "- [x] done", "<ul>\n<li class=\"task\"><input type=\"checkbox\" disabled checked> done\n</li>\n</ul>\n"},
+		{"table", "| A | B |\n|:--|--:|\n| 1 | 2 |", "<table>\n<thead><tr><th style=\"text-align: left\">A</th><th style=\"text-align: right\">B</th></tr></thead>\n<tbody>\n<tr><td style=\"text-align: left\">1</td><td style=\"text-align: right\">2</td></tr>\n</tbody>\n</table>\n"},
+		{"blockquote", "> note", "<blockquote>\n<p>note</p>\n</blockquote>\n"},
+		{"rule", "---", "<hr>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, _ := renderMarkdown(tt.in, nil)
+			if got != tt.want {
+				t.Errorf("renderMarkdown(%q) =\n%s\nwant\n%s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownHeadings(t *testing.T) {
+	src := "# Title\n\nIntro text.\n\n## Write Your dev_startup.sh\n\nBody.\n\n## Write Your dev_startup.sh\n\nAgain."
+	out, headings, sections := renderMarkdown(src, nil)
+
+	wantIDs := []string{"title", "write-your-dev_startupsh", "write-your-dev_startupsh-1"}
+	if len(headings) != len(wantIDs) {
+		t.Fatalf("got %d headings, want %d", len(headings), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if headings[i].ID != id {
+			t.Errorf("heading %d ID = %q, want %q", i, headings[i].ID, id)
+		}
+		if !strings.Contains(out, `id="`+id+`"`) {
+			t.Errorf("output has no element with id %q", id)
+		}
+	}
+	if len(sections) != 3 || sections[0].Text != "Intro text." || sections[2].Text != "Again." {
+		t.Errorf("unexpected sections: %+v", sections)
+	}
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// appExamplesURL is where the scripts the templates are modeled on live
+const appExamplesURL = templateRepoURL + "/tree/main/hot-reload-template/app-examples"
+
+// startupTemplates are dev_startup.sh templates, one per project type
+//
+//go:embed startup-templates/*.sh.tmpl
+var startupTemplates embed.FS
+
+var startupTemplate = template.Must(template.ParseFS(startupTemplates, "startup-templates/*.sh.tmpl"))
+
+// Project describes the app found in the workspace and the dev_startup.sh
+// suggested for it
+type Project struct {
+	Detected         bool     `json:"detected"`
+	Type             string   `json:"type,omitempty"`
+	Framework        string   `json:"framework,omitempty"`
+	Label            string   `json:"label,omitempty"`
+	Evidence         []string `json:"evidence,omitempty"`
+	PackageManager   string   `json:"package_manager,omitempty"`
+	Notes            []string `json:"notes,omitempty"`
+	Example          string   `json:"example,omitempty"`
+	HasStartupScript bool     `json:"has_startup_script"`
+	Script           string   `json:"script,omitempty"`
+
+	// Template inputs
+	LockFile       string `json:"-"`
+	InstallCommand string `json:"-"`
+	PrepareCommand string `json:"-"`
+	DevCommand     string `json:"-"`
+	NodemonArgs    string `json:"-"`
+	BuildTarget    string `json:"-"`
+	Binary         string `json:"-"`
+}
+
+// projectDetectors are tried in order. Ruby, Go, Rust and Python apps often
+// carry a package.json for their assets, so Node comes last.
+var projectDetectors = []func(dir string) (Project, bool){
+	detectRuby,
+	detectGo,
+	detectRust,
+	detectPython,
+	detectNode,
+}
+
+// detectProject inspects dir and renders a dev_startup.sh for what it finds
+func detectProject(dir string) Project {
+	for _, detect := range projectDetectors {
+		project, ok := detect(dir)
+		if !ok {
+			continue
+		}
+		project.Detected = true
+		project.HasStartupScript = fileExists(filepath.Join(dir, "dev_startup.sh"))
+
+		var script bytes.Buffer
+		if err := startupTemplate.ExecuteTemplate(&script, project.Type+".sh.tmpl", project); err != nil {
+			log.Printf("Error rendering dev_startup.sh for %s: %v", project.Type, err)
+			return Project{}
+		}
+		project.Script = script.String()
+		return project
+	}
+	return Project{}
+}
+
+// detectGo finds go.mod and the package to build
+func detectGo(dir string) (Project, bool) {
+	if !fileExists(filepath.Join(dir, "go.mod")) {
+		return Project{}, false
+	}
+	project := Project{
+		Type:        "go",
+		Label:       "Go",
+		Evidence:    []string{"go.mod"},
+		Example:     "go-sample-app",
+		BuildTarget: ".",
+	}
+	if fileExists(filepath.Join(dir, "go.sum")) {
+		project.Evidence = append(project.Evidence, "go.sum")
+	}
+
+	if !hasGoMain(dir) {
+		// cmd/<name>/main.go is the usual layout when the root is a library
+		commands, _ := filepath.Glob(filepath.Join(dir, "cmd", "*"))
+		var mains []string
+		for _, command := range commands {
+			if hasGoMain(command) {
+				mains = append(mains, "./cmd/"+filepath.Base(command))
+			}
+		}
+		switch len(mains) {
+		case 0:
+			project.Notes = append(project.Notes, "No main package was found in the root or cmd/; set BUILD_TARGET in the script to the package to run.")
+		case 1:
+			project.BuildTarget = mains[0]
+		default:
+			project.BuildTarget = mains[0]
+			project.Notes = append(project.Notes, fmt.Sprintf("Several commands were found (%s); the script builds %s.", strings.Join(mains, ", "), mains[0]))
+		}
+	}
+	project.Notes = append(project.Notes, "Your app must listen on the port in the PORT environment variable (the script sets it).")
+	return project, true
+}
+
+// goMainPattern matches a "package main" clause
+var goMainPattern = regexp.MustCompile(`(?m)^package main\b`)
+
+// hasGoMain reports whether dir holds a non-test Go file in package main
+func hasGoMain(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if data, err := os.ReadFile(file); err == nil && goMainPattern.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// packageJSON is the subset of package.json used to pick a dev command
+type packageJSON struct {
+	Main            string            `json:"main"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// nodeFrameworks are dev servers that take host and port flags, most specific first
+var nodeFrameworks = []struct {
+	dependency string
+	framework  string
+	label      string
+	flags      string
+}{
+	{"next", "nextjs", "Next.js", `--hostname 0.0.0.0 --port "$PORT"`},
+	{"nuxt", "nuxt", "Nuxt", `--host 0.0.0.0 --port "$PORT"`},
+	{"astro", "astro", "Astro", `--host 0.0.0.0 --port "$PORT"`},
+	{"vite", "vite", "Vite", `--host 0.0.0.0 --port "$PORT"`},
+}
+
+// nodeLockFiles map lock files to the package manager that writes them
+var nodeLockFiles = []struct {
+	file    string
+	manager string
+	install string
+}{
+	{"pnpm-lock.yaml", "pnpm", "pnpm install"},
+	{"yarn.lock", "yarn", "yarn install"},
+	{"package-lock.json", "npm", "npm install"},
+}
+
+// detectNode reads package.json for the framework and the lock file for the package manager
+func detectNode(dir string) (Project, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return Project{}, false
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return Project{}, false
+	}
+
+	project := Project{
+		Type:           "node",
+		Label:          "Node.js",
+		Evidence:       []string{"package.json"},
+		Example:        "blank-nodejs-template",
+		PackageManager: "npm",
+		LockFile:       "package-lock.json",
+		InstallCommand: "npm install",
+	}
+	for _, lock := range nodeLockFiles {
+		if fileExists(filepath.Join(dir, lock.file)) {
+			project.PackageManager, project.LockFile, project.InstallCommand = lock.manager, lock.file, lock.install
+			project.Evidence = append(project.Evidence, lock.file)
+			break
+		}
+	}
+	if project.PackageManager != "npm" {
+		project.Notes = append(project.Notes, fmt.Sprintf("%s is enabled through corepack, which ships with Node.js 16.10 and later.", project.PackageManager))
+	}
+
+	// npm needs "--" to pass flags on to the script; pnpm and yarn pass them directly
+	runDev := project.PackageManager + " run dev"
+	flagSeparator := " "
+	if project.PackageManager == "npm" {
+		flagSeparator = " -- "
+	}
+
+	for _, fw := range nodeFrameworks {
+		if !hasDependency(pkg, fw.dependency) {
+			continue
+		}
+		project.Framework, project.Label = fw.framework, fw.label
+		if fw.framework == "nextjs" {
+			project.Example = "nextjs-sample-app"
+		}
+		if _, ok := pkg.Scripts["dev"]; ok {
+			project.DevCommand = runDev + flagSeparator + fw.flags
+		} else {
+			project.DevCommand = "npx " + fw.dependency + " dev " + fw.flags
+		}
+		project.NodemonArgs = "--watch package.json --watch " + project.LockFile
+		return project, true
+	}
+
+	if hasDependency(pkg, "express") {
+		project.Framework, project.Label = "express", "Node.js (Express)"
+	}
+	if _, ok := pkg.Scripts["dev"]; ok {
+		// The dev script does its own reloading; nodemon only restarts it for dependency changes
+		project.DevCommand = runDev
+		project.NodemonArgs = "--watch package.json --watch " + project.LockFile
+	} else {
+		main := pkg.Main
+		if main == "" {
+			main = "index.js"
+		}
+		project.DevCommand = "node " + main
+		project.NodemonArgs = "--watch . --ext js,mjs,cjs,json"
+	}
+	project.Notes = append(project.Notes, "Your app must listen on the port in the PORT environment variable (the script sets it).")
+	return project, true
+}
+
+// hasDependency reports whether package.json depends on name at runtime or dev time
+func hasDependency(pkg packageJSON, name string) bool {
+	_, ok := pkg.Dependencies[name]
+	_, devOK := pkg.DevDependencies[name]
+	return ok || devOK
+}
+
+// pythonAppPattern finds "app = FastAPI(" or "app = Flask(" in an entry point
+var pythonAppPattern = regexp.MustCompile(`(?m)^(\w+)\s*=\s*(FastAPI|Flask)\(`)
+
+// pythonEntryPoints are the files searched for the app object, in order
+var pythonEntryPoints = []string{"main.py", "app.py", "app/main.py", "src/main.py", "src/app/main.py"}
+
+// detectPython looks for a uv project and the web framework it depends on
+func detectPython(dir string) (Project, bool) {
+	pyproject, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+	hasLock := fileExists(filepath.Join(dir, "uv.lock"))
+	if err != nil && !hasLock {
+		return Project{}, false
+	}
+
+	project := Project{
+		Type:           "python",
+		Label:          "Python",
+		Example:        "python-fastapi-sample",
+		PackageManager: "uv",
+		LockFile:       "uv.lock",
+		InstallCommand: "uv sync --no-dev",
+	}
+	if err == nil {
+		project.Evidence = append(project.Evidence, "pyproject.toml")
+	}
+	if hasLock {
+		project.Evidence = append(project.Evidence, "uv.lock")
+	} else {
+		project.Notes = append(project.Notes, "There is no uv.lock yet; uv creates one on the first sync. Commit it so every sync installs the same versions.")
+	}
+
+	dependencies := strings.ToLower(string(pyproject))
+	switch {
+	case strings.Contains(dependencies, "django") && fileExists(filepath.Join(dir, "manage.py")):
+		project.Framework, project.Label = "django", "Django"
+		project.Evidence = append(project.Evidence, "manage.py")
+		project.PrepareCommand = "uv run python manage.py migrate"
+		project.DevCommand = `uv run python manage.py runserver "0.0.0.0:$PORT"`
+	case strings.Contains(dependencies, "fastapi"):
+		project.Framework, project.Label = "fastapi", "FastAPI"
+		module := pythonAppModule(dir, "FastAPI")
+		project.DevCommand = fmt.Sprintf(`uv run uvicorn %s --host 0.0.0.0 --port "$PORT" --reload`, module)
+	case strings.Contains(dependencies, "flask"):
+		project.Framework, project.Label = "flask", "Flask"
+		module := pythonAppModule(dir, "Flask")
+		project.DevCommand = fmt.Sprintf(`uv run flask --app %s run --debug --host 0.0.0.0 --port "$PORT"`, module)
+	default:
+		project.DevCommand = "uv run python main.py"
+		project.Notes = append(project.Notes, "No FastAPI, Flask or Django dependency was found; adjust the start command and make the app listen on $PORT.")
+	}
+	return project, true
+}
+
+// pythonAppModule returns the "module:object" of the first entry point
+// creating a framework app, defaulting to main:app
+func pythonAppModule(dir, framework string) string {
+	for _, entry := range pythonEntryPoints {
+		data, err := os.ReadFile(filepath.Join(dir, entry))
+		if err != nil {
+			continue
+		}
+		for _, match := range pythonAppPattern.FindAllSubmatch(data, -1) {
+			if string(match[2]) == framework {
+				module := strings.ReplaceAll(strings.TrimSuffix(entry, ".py"), "/", ".")
+				module = strings.TrimPrefix(module, "src.")
+				return module + ":" + string(match[1])
+			}
+		}
+	}
+	return "main:app"
+}
+
+// railsGemPattern matches a rails gem line in a Gemfile
+var railsGemPattern = regexp.MustCompile(`(?m)^\s*gem\s+["']rails["']`)
+
+// detectRuby looks for a Gemfile, and Rails or a Rack config.ru to serve
+func detectRuby(dir string) (Project, bool) {
+	gemfile, err := os.ReadFile(filepath.Join(dir, "Gemfile"))
+	if err != nil {
+		return Project{}, false
+	}
+	project := Project{
+		Type:           "ruby",
+		Label:          "Ruby",
+		Evidence:       []string{"Gemfile"},
+		Example:        "ruby-rails-sample",
+		PackageManager: "bundler",
+		LockFile:       "Gemfile.lock",
+		InstallCommand: "bundle install --jobs=4 --retry=3",
+	}
+	if fileExists(filepath.Join(dir, "Gemfile.lock")) {
+		project.Evidence = append(project.Evidence, "Gemfile.lock")
+	}
+
+	switch {
+	case railsGemPattern.Match(gemfile) || fileExists(filepath.Join(dir, "config", "application.rb")):
+		project.Framework, project.Label = "rails", "Ruby on Rails"
+		project.PrepareCommand = "bundle exec rails db:prepare"
+		project.DevCommand = `bundle exec rails server -b 0.0.0.0 -p "$PORT"`
+	case fileExists(filepath.Join(dir, "config.ru")):
+		project.Framework, project.Label = "rack", "Ruby (Rack)"
+		project.Evidence = append(project.Evidence, "config.ru")
+		project.DevCommand = `bundle exec rackup -o 0.0.0.0 -p "$PORT"`
+		project.Notes = append(project.Notes, "rackup does not reload code; add rerun or a framework reloader to pick up changes without a restart.")
+	default:
+		project.DevCommand = "bundle exec ruby app.rb"
+		project.Notes = append(project.Notes, "Neither Rails nor a config.ru was found; adjust the start command and make the app listen on $PORT.")
+	}
+	return project, true
+}
+
+// detectRust reads the binary name from Cargo.toml
+func detectRust(dir string) (Project, bool) {
+	file, err := os.Open(filepath.Join(dir, "Cargo.toml"))
+	if err != nil {
+		return Project{}, false
+	}
+	defer file.Close()
+
+	project := Project{
+		Type:     "rust",
+		Label:    "Rust",
+		Evidence: []string{"Cargo.toml"},
+		Example:  "go-sample-app",
+		LockFile: "Cargo.lock",
+	}
+	if fileExists(filepath.Join(dir, "Cargo.lock")) {
+		project.Evidence = append(project.Evidence, "Cargo.lock")
+	}
+
+	// The first name in [package] or [[bin]] is the binary cargo builds
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() && project.Binary == "" {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" && (section == "[package]" || section == "[[bin]]") {
+			project.Binary = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	if project.Binary == "" {
+		project.Binary = "app"
+		project.Notes = append(project.Notes, "No package name was found in Cargo.toml; set BINARY in the script.")
+	}
+	project.Notes = append(project.Notes,
+		"Rust is only installed when the INSTALL_RUST build argument is true.",
+		"Your app must listen on the port in the PORT environment variable (the script sets it).")
+	return project, true
+}
+
+// projectWorkspace is the directory dev_startup.sh runs in
+func projectWorkspace() string {
+	return getEnvOrDefault("WORKSPACE_PATH", "/workspaces/app")
+}
+
+// projectHandler handles requests to the /api/project endpoint
+func projectHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/project endpoint
+	if r.URL.Path != "/api/project" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(detectProject(projectWorkspace())); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// startupScriptHandler serves the suggested script as a download, so it can be
+// fetched with curl straight into the repository
+func startupScriptHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /dev_startup.sh endpoint
+	if r.URL.Path != "/dev_startup.sh" {
+		http.NotFound(w, r)
+		return
+	}
+
+	project := detectProject(projectWorkspace())
+	if !project.Detected {
+		http.Error(w, "No Go, Node.js, Python, Ruby or Rust project found in the workspace", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-shellscript; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="dev_startup.sh"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(project.Script)); err != nil {
+		log.Printf("Error writing dev_startup.sh: %v", err)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// startupPhasesFile mirrors STARTUP_PHASES_FILE in startup.sh
+const startupPhasesFile = "/tmp/dev-startup-phases.jsonl"
+
+// devPathPrefix is where the welcome server's own pages live once the app owns "/"
+const devPathPrefix = "/_dev"
+
+// upstreamCheckInterval is how often the proxy dials the app to track its state
+const upstreamCheckInterval = time.Second
+
+// App states shown on the status page while the upstream is down
+const (
+	appStarting   = "starting"
+	appRestarting = "restarting"
+	appCrashed    = "crashed"
+)
+
+// appProxy keeps the welcome server on the public port and forwards to the
+// app on an internal port. Requests it cannot forward get a status page that
+// refreshes itself until the app answers again.
+type appProxy struct {
+	port       int
+	target     *url.URL
+	crashAfter time.Duration
+	local      http.Handler
+	proxy      *httputil.ReverseProxy
+
+	mu        sync.Mutex
+	up        bool
+	everUp    bool
+	changedAt time.Time
+	lastError string
+	phase     string
+}
+
+// StatusPageData holds data for the status page template
+type StatusPageData struct {
+	State     string
+	Title     string
+	Detail    string
+	LastError string
+	Phase     string
+	Port      int
+	DevPath   string
+	Timestamp string
+}
+
+// newAppProxy creates a proxy to the app on 127.0.0.1:port; local serves the
+// welcome server's own routes under devPathPrefix and when there is no app
+func newAppProxy(port int, crashAfter time.Duration, local http.Handler) *appProxy {
+	p := &appProxy{
+		port:       port,
+		target:     &url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", port)},
+		crashAfter: crashAfter,
+		local:      local,
+		changedAt:  time.Now(),
+	}
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite:        p.rewrite,
+		FlushInterval:  -1, // flush immediately so streamed responses are not buffered
+		ModifyResponse: p.modifyResponse,
+		ErrorHandler:   p.errorHandler,
+	}
+	return p
+}
+
+// Watch dials the app every second so the status page can tell a first start
+// from a restart, and logs each transition
+func (p *appProxy) Watch(ctx context.Context) {
+	ticker := time.NewTicker(upstreamCheckInterval)
+	defer ticker.Stop()
+	for {
+		conn, err := net.DialTimeout("tcp", p.target.Host, upstreamCheckInterval)
+		if err == nil {
+			conn.Close()
+			p.setUp(true, "")
+		} else {
+			p.setUp(false, dialError(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setUp records the latest upstream result
+func (p *appProxy) setUp(up bool, errMsg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !up {
+		p.lastError = errMsg
+	}
+	if up == p.up {
+		return
+	}
+	p.up = up
+	p.changedAt = time.Now()
+	if up {
+		p.everUp = true
+		log.Printf("App on port %d is up", p.port)
+		activity.Publish(ActivityEvent{Type: "app_up", Level: levelSuccess, Message: fmt.Sprintf("Your app is answering on port %d", p.port)})
+	} else if p.everUp {
+		log.Printf("App on port %d stopped answering: %s", p.port, errMsg)
+		activity.Publish(ActivityEvent{Type: "app_down", Level: levelError, Message: "Your app stopped answering: " + errMsg})
+	}
+}
+
+// isUp reports whether the app answered the latest check
+func (p *appProxy) isUp() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.up
+}
+
+// state describes why the app cannot be reached, for the status page
+func (p *appProxy) state(now time.Time) StatusPageData {
+	phase := p.currentPhase()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	down := now.Sub(p.changedAt).Truncate(time.Second)
+	data := StatusPageData{
+		LastError: p.lastError,
+		Phase:     phase,
+		Port:      p.port,
+		DevPath:   devPathPrefix + "/",
+		Timestamp: now.UTC().Format(time.RFC3339),
+	}
+
+	switch {
+	case phase != "" && phase != "app":
+		data.State, data.Title = appStarting, "Your app is starting"
+		data.Detail = fmt.Sprintf("The container is still starting up (phase: %s).", phase)
+		data.LastError = ""
+	case !p.everUp:
+		data.State, data.Title = appStarting, "Your app is starting"
+		data.Detail = fmt.Sprintf("Waiting for your app to listen on port %d (%s so far).", p.port, down)
+	case down < p.crashAfter:
+		data.State, data.Title = appRestarting, "Your app is restarting"
+		data.Detail = fmt.Sprintf("Your app stopped answering %s ago, usually because it is reloading after a change.", down)
+	default:
+		data.State, data.Title = appCrashed, "Your app has crashed"
+		data.Detail = fmt.Sprintf("Your app has not answered on port %d for %s. Check the container logs for a build error or crash.", p.port, down)
+	}
+	return data
+}
+
+// currentPhase returns the latest startup phase, or "" when startup.sh has not
+// written one (e.g. the server runs standalone). Final phases are cached.
+func (p *appProxy) currentPhase() string {
+	p.mu.Lock()
+	phase := p.phase
+	p.mu.Unlock()
+	if phase == "app" || phase == "no_app" {
+		return phase
+	}
+
+	phase = readCurrentPhase()
+	p.mu.Lock()
+	p.phase = phase
+	p.mu.Unlock()
+	return phase
+}
+
+// readCurrentPhase returns the phase of the last line of the startup phase log
+func readCurrentPhase() string {
+	file, err := os.Open(startupPhasesFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Error reading startup phases: %v", err)
+		}
+		return ""
+	}
+	defer file.Close()
+
+	phase := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record struct {
+			Phase string `json:"phase"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil && record.Phase != "" {
+			phase = record.Phase
+		}
+	}
+	return phase
+}
+
+// ServeHTTP routes devPathPrefix to the welcome server, serves the welcome
+// page when there is no app to run, and proxies everything else
+func (p *appProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == devPathPrefix {
+		http.Redirect(w, r, devPathPrefix+"/", http.StatusFound)
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, devPathPrefix+"/") {
+		http.StripPrefix(devPathPrefix, p.local).ServeHTTP(w, r)
+		return
+	}
+	if p.currentPhase() == "no_app" {
+		p.local.ServeHTTP(w, r)
+		return
+	}
+	p.proxy.ServeHTTP(w, r)
+}
+
+// rewrite forwards the request unchanged apart from the target. The original
+// Host and any X-Forwarded-* headers from the platform's load balancer are
+// kept, so the app builds the same URLs as when it serves the port directly.
+func (p *appProxy) rewrite(r *httputil.ProxyRequest) {
+	r.SetURL(p.target)
+	r.Out.Host = r.In.Host
+	r.Out.Header["X-Forwarded-For"] = r.In.Header["X-Forwarded-For"]
+	r.SetXForwarded()
+	for _, header := range []string{"X-Forwarded-Host", "X-Forwarded-Proto"} {
+		if value := r.In.Header.Get(header); value != "" {
+			r.Out.Header.Set(header, value)
+		}
+	}
+}
+
+// modifyResponse marks the app as up as soon as it answers a request
+func (p *appProxy) modifyResponse(*http.Response) error {
+	p.setUp(true, "")
+	return nil
+}
+
+// errorHandler serves the status page when the app cannot be reached
+func (p *appProxy) errorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	if r.Context().Err() != nil {
+		// The client went away; there is nobody to answer
+		return
+	}
+	p.setUp(false, dialError(err))
+	data := p.state(time.Now())
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", "2")
+
+	// Browsers get a page that refreshes itself; API clients and WebSockets a short error
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "%s: %s\n", data.Title, data.Detail)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := statusPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Error executing template: %v", err)
+	}
+}
+
+// dialError strips the address from a connection error, leaving e.g. "connection refused"
+func dialError(err error) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil {
+		var sysErr *os.SyscallError
+		if errors.As(opErr.Err, &sysErr) {
+			return sysErr.Err.Error()
+		}
+		return opErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// statusPageTemplate is shown in place of the app while it is down
+var statusPageTemplate = template.Must(template.New("status").Parse(statusPageHTML))
+
+// statusPageHTML is the HTML template for the starting / restarting / crashed page
+const statusPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta http-equiv="refresh" content="2">
+    <title>{{.Title}}</title>
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            min-height: 100vh;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+            padding: 20px;
+        }
+        .container {
+            background: white;
+            border-radius: 12px;
+            box-shadow: 0 20px 60px rgba(0, 0, 0, 0.3);
+            max-width: 640px;
+            width: 100%;
+            padding: 40px;
+            text-align: center;
+        }
+        .icon {
+            font-size: 3em;
+            margin-bottom: 15px;
+        }
+        .starting .icon, .restarting .icon {
+            animation: spin 2s linear infinite;
+            display: inline-block;
+        }
+        @keyframes spin {
+            from { transform: rotate(0deg); }
+            to { transform: rotate(360deg); }
+        }
+        h1 {
+            color: #333;
+            font-size: 1.8em;
+            margin-bottom: 15px;
+        }
+        p {
+            color: #555;
+            line-height: 1.6;
+            margin-bottom: 10px;
+        }
+        .error {
+            background: #f8d7da;
+            border-left: 4px solid #dc3545;
+            color: #721c24;
+            font-family: 'Courier New', monospace;
+            font-size: 0.9em;
+            padding: 10px 15px;
+            margin: 15px 0;
+            text-align: left;
+            border-radius: 4px;
+        }
+        .footer {
+            color: #999;
+            font-size: 0.85em;
+            margin-top: 25px;
+        }
+        .footer a {
+            color: #667eea;
+        }
+    </style>
+</head>
+<body>
+    <div class="container {{.State}}">
+        <div class="icon">{{if eq .State "crashed"}}💥{{else}}⚙️{{end}}</div>
+        <h1>{{.Title}}</h1>
+        <p>{{.Detail}}</p>
+        {{if .LastError}}<div class="error">127.0.0.1:{{.Port}}: {{.LastError}}</div>{{end}}
+        <p>This page refreshes automatically and switches to your app as soon as it answers.</p>
+        <div class="footer">
+            <p>{{.Timestamp}} · <a href="{{.DevPath}}">Dev environment status and configuration check</a></p>
+        </div>
+    </div>
+</body>
+</html>
+`
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+// startPTY starts cmd on a new pseudo-terminal as the leader of its own
+// session, with the terminal as its controlling tty, and returns the master
+func startPTY(cmd *exec.Cmd, cols, rows uint16) (*os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// unlockpt and ptsname, without cgo
+	var unlock int32
+	if err := ioctl(master, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("unlocking pty: %w", err)
+	}
+	var number uint32
+	if err := ioctl(master, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&number))); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("reading pty number: %w", err)
+	}
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", number), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+	// The child has its own copy; the master is all the server needs
+	defer tty.Close()
+
+	if err := setPTYSize(master, cols, rows); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0 // stdin in the child
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// setPTYSize tells the terminal its window size; the shell gets SIGWINCH
+func setPTYSize(master *os.File, cols, rows uint16) error {
+	size := struct{ rows, cols, xpixel, ypixel uint16 }{rows: rows, cols: cols}
+	return ioctl(master, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&size)))
+}
+
+// ioctl issues an ioctl on f
+func ioctl(f *os.File, request, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg)
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
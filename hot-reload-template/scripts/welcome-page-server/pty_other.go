@@ -0,0 +1,22 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// errNoPTY is returned outside Linux; the dev container always runs Linux
+var errNoPTY = errors.New("the web terminal is only supported on Linux")
+
+// startPTY is unavailable outside Linux
+func startPTY(*exec.Cmd, uint16, uint16) (*os.File, error) {
+	return nil, errNoPTY
+}
+
+// setPTYSize is unavailable outside Linux
+func setPTYSize(*os.File, uint16, uint16) error {
+	return errNoPTY
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redactedMask replaces every hidden value
+const redactedMask = "***"
+
+// secretKeyWords mark an environment variable as secret when they appear as
+// one of its underscore-separated words (GITHUB_TOKEN, SECRET_KEY_BASE, ...)
+var secretKeyWords = map[string]bool{
+	"TOKEN":       true,
+	"SECRET":      true,
+	"PASSWORD":    true,
+	"PASSWD":      true,
+	"KEY":         true,
+	"APIKEY":      true,
+	"CREDENTIALS": true,
+}
+
+// secretQueryParams are URL query parameters whose values are hidden
+var secretQueryParams = []string{"token", "access_token", "password", "secret", "key", "api_key", "apikey", "sig", "signature"}
+
+// urlPattern finds URLs inside free text such as git error messages
+var urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^\s'"<>]+`)
+
+// isSecretKey reports whether an environment variable name looks like a secret
+func isSecretKey(key string) bool {
+	for _, word := range strings.Split(strings.ToUpper(key), "_") {
+		if secretKeyWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue masks a configuration value for display: secret keys are hidden
+// entirely, anything else has credentials stripped from embedded URLs
+func redactValue(key, value string) string {
+	if value == "" {
+		return value
+	}
+	if isSecretKey(key) {
+		return redactedMask
+	}
+	return redactText(value)
+}
+
+// redactText masks credentials in every URL found in s
+func redactText(s string) string {
+	return urlPattern.ReplaceAllStringFunc(s, redactURL)
+}
+
+// redactURL masks the userinfo and secret query parameters of a URL.
+// It works on the raw string rather than url.Parse, which rejects many
+// real-world forms (unescaped @ / ? # or % in tokens) that still leak secrets.
+// "user:pass@" keeps the user name; a bare "token@" is masked whole.
+func redactURL(raw string) string {
+	schemeEnd := strings.Index(raw, "://")
+	if schemeEnd < 0 {
+		return raw
+	}
+	prefix, rest := raw[:schemeEnd+3], raw[schemeEnd+3:]
+
+	// The last @ before the first / ? or # ends the userinfo, so an
+	// unescaped @ in a password is covered
+	at := strings.LastIndex(rest[:authorityEnd(rest)], "@")
+	if at < 0 {
+		at = userinfoAt(rest)
+	}
+	if at < 0 {
+		return prefix + redactQuery(rest)
+	}
+
+	userinfo, rest := rest[:at], rest[at+1:]
+	end := authorityEnd(rest)
+	host, tail := rest[:end], rest[end:]
+	if user, _, hasPassword := strings.Cut(userinfo, ":"); hasPassword {
+		userinfo = user + ":" + redactedMask
+	} else {
+		userinfo = redactedMask
+	}
+	return prefix + userinfo + "@" + host + redactQuery(tail)
+}
+
+// authorityEnd is the index of the first / ? or # in s, or len(s)
+func authorityEnd(s string) int {
+	if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// userinfoAt finds the @ ending a userinfo that holds an unescaped / ? or #,
+// which cut the authority short before its @. That is the case when what
+// precedes the first delimiter cannot be a host: "user:" followed by
+// something other than a port, or a bare token with no dot. The userinfo then
+// ends at the last @ before the host's first /. Returns -1 when there is none.
+func userinfoAt(rest string) int {
+	authority := rest[:authorityEnd(rest)]
+	if strings.HasPrefix(authority, "[") {
+		return -1
+	}
+	colon := strings.Index(authority, ":")
+	switch {
+	case colon >= 0 && isPortNumber(authority[colon+1:]):
+		return -1
+	case colon < 0 && (strings.Contains(authority, ".") || authority == "localhost"):
+		return -1
+	}
+	first := strings.Index(rest, "@")
+	if first < len(authority) {
+		return -1
+	}
+	hostEnd := len(rest)
+	if i := strings.Index(rest[first:], "/"); i >= 0 {
+		hostEnd = first + i
+	}
+	return strings.LastIndex(rest[:hostEnd], "@")
+}
+
+// isPortNumber reports whether s is empty or all digits, as a URL port is
+func isPortNumber(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// redactQuery masks secret parameter values in the path/query/fragment part
+func redactQuery(tail string) string {
+	queryStart := strings.Index(tail, "?")
+	if queryStart < 0 {
+		return tail
+	}
+	query := tail[queryStart+1:]
+	fragment := ""
+	if i := strings.Index(query, "#"); i >= 0 {
+		query, fragment = query[:i], query[i:]
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, _, hasValue := strings.Cut(param, "=")
+		if hasValue && isSecretParam(name) {
+			params[i] = name + "=" + redactedMask
+		}
+	}
+	return tail[:queryStart+1] + strings.Join(params, "&") + fragment
+}
+
+// isSecretParam reports whether a query parameter name carries a secret
+func isSecretParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretQueryParams {
+		if name == secret {
+			return true
+		}
+	}
+	return isSecretKey(name)
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// statusAPIVersion is bumped whenever a field of StatusResponse changes meaning
+// or is removed; new fields may be added within a version
+const statusAPIVersion = "v1"
+
+// syncStateFile mirrors SYNC_STATE_FILE in github-sync.sh
+const syncStateFile = "/tmp/dev-sync-state.json"
+
+// lastJobCommitFile mirrors LAST_JOB_COMMIT_FILE in job-manager.sh; it holds
+// the commit deploy jobs last completed for
+const lastJobCommitFile = "/tmp/last_job_commit.txt"
+
+// appPIDFile is where startup.sh records the app's PID
+const appPIDFile = "/tmp/dev-pids/app.pid"
+
+// statusConfigKeys is the configuration reported by the status API, with the
+// defaults the scripts apply when a variable is unset
+var statusConfigKeys = []struct {
+	key          string
+	defaultValue string
+}{
+	{"GITHUB_REPO_URL", ""},
+	{"GITHUB_REPO_FOLDER", ""},
+	{"GITHUB_BRANCH", ""},
+	{"GITHUB_TOKEN", ""},
+	{"GITHUB_SYNC_INTERVAL", "15"},
+	{"WORKSPACE_PATH", "/workspaces/app"},
+	{"DEV_START_COMMAND", ""},
+	{"PRE_DEPLOY_COMMAND", ""},
+	{"PRE_DEPLOY_FOLDER", ""},
+	{"PRE_DEPLOY_REPO_URL", ""},
+	{"PRE_DEPLOY_TIMEOUT", "300"},
+	{"POST_DEPLOY_COMMAND", ""},
+	{"POST_DEPLOY_FOLDER", ""},
+	{"POST_DEPLOY_REPO_URL", ""},
+	{"POST_DEPLOY_TIMEOUT", "300"},
+	{"ENABLE_DEV_HEALTH", "true"},
+	{"ENABLE_WELCOME_PROXY", "false"},
+	{"WELCOME_PROXY_APP_PORT", "3000"},
+	{"ENABLE_LOG_CAPTURE", "true"},
+	{"ENABLE_WEB_TERMINAL", "false"},
+	{"WEB_TERMINAL_RECORD", "false"},
+}
+
+// runtimeCommands are the version commands startup.sh uses to list installed runtimes
+var runtimeCommands = []struct {
+	name string
+	args []string
+}{
+	{"node", []string{"node", "--version"}},
+	{"python", []string{"python3", "--version"}},
+	{"go", []string{"go", "env", "GOVERSION"}},
+	{"rust", []string{"rustc", "--version"}},
+	{"ruby", []string{"ruby", "--version"}},
+}
+
+// StatusResponse is the JSON response for the /api/v1/status endpoint
+type StatusResponse struct {
+	APIVersion     string            `json:"api_version"`
+	Timestamp      string            `json:"timestamp"`
+	Live           bool              `json:"live"`
+	NotLiveReasons []string          `json:"not_live_reasons"`
+	Config         map[string]string `json:"config"`
+	Deploy         DeployStatus      `json:"deploy"`
+	Sync           SyncStatus        `json:"sync"`
+	Jobs           JobsStatus        `json:"jobs"`
+	App            AppStatus         `json:"app"`
+	Runtimes       map[string]string `json:"runtimes"`
+	Project        *ProjectSummary   `json:"project,omitempty"`
+}
+
+// DeployStatus is the commit the workspace is on
+type DeployStatus struct {
+	Commit          string `json:"commit,omitempty"`
+	CommitTime      string `json:"commit_time,omitempty"`
+	Branch          string `json:"branch,omitempty"`
+	RequestedCommit string `json:"requested_commit,omitempty"`
+	CommitMatches   *bool  `json:"commit_matches,omitempty"`
+}
+
+// SyncStatus is the sync loop's counters from github-sync.sh
+type SyncStatus struct {
+	Running     bool           `json:"running"`
+	Attempts    int64          `json:"attempts"`
+	Failures    int64          `json:"failures"`
+	LastAttempt string         `json:"last_attempt,omitempty"`
+	LastSuccess string         `json:"last_success,omitempty"`
+	LastEvent   *ActivityEvent `json:"last_event,omitempty"`
+}
+
+// JobsStatus reports the deploy jobs and the commit they last completed for
+type JobsStatus struct {
+	CompletedCommit string               `json:"completed_commit,omitempty"`
+	Jobs            map[string]JobStatus `json:"jobs"`
+}
+
+// JobStatus is one deploy job's configuration and its last run
+type JobStatus struct {
+	Configured      bool   `json:"configured"`
+	Status          string `json:"status,omitempty"`
+	Commit          string `json:"commit,omitempty"`
+	ExitCode        *int   `json:"exit_code,omitempty"`
+	StartedAt       string `json:"started_at,omitempty"`
+	FinishedAt      string `json:"finished_at,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds,omitempty"`
+}
+
+// AppStatus describes the user's app process
+type AppStatus struct {
+	State   string `json:"state"`
+	Phase   string `json:"phase,omitempty"`
+	PID     int    `json:"pid,omitempty"`
+	Running bool   `json:"running"`
+	Proxy   bool   `json:"proxy"`
+	Port    int    `json:"port,omitempty"`
+	Up      *bool  `json:"up,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// ProjectSummary is the project detected in the workspace, without the script
+type ProjectSummary struct {
+	Type           string `json:"type"`
+	Framework      string `json:"framework,omitempty"`
+	PackageManager string `json:"package_manager,omitempty"`
+}
+
+// App states reported by the status API
+const (
+	appNotConfigured = "not_configured"
+	appRunning       = "running"
+	appExited        = "exited"
+)
+
+// upstream is the app proxy in proxy mode, nil otherwise
+var upstream *appProxy
+
+// buildStatus gathers everything the status API reports. requestedCommit, if
+// set, must prefix the deployed commit for the push to count as live.
+func buildStatus(requestedCommit string) StatusResponse {
+	status := StatusResponse{
+		APIVersion:     statusAPIVersion,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		NotLiveReasons: []string{},
+		Config:         make(map[string]string),
+		Runtimes:       installedRuntimes(),
+	}
+	for _, setting := range statusConfigKeys {
+		status.Config[setting.key] = redactValue(setting.key, getEnvOrDefault(setting.key, setting.defaultValue))
+	}
+
+	src := currentConfig()
+	status.Deploy = readDeployStatus(src, requestedCommit)
+	status.Sync = readSyncStatus()
+	status.Jobs = readJobsStatus()
+	status.App = readAppStatus()
+	if project := detectProject(projectWorkspace()); project.Detected {
+		status.Project = &ProjectSummary{Type: project.Type, Framework: project.Framework, PackageManager: project.PackageManager}
+	}
+
+	status.NotLiveReasons = notLiveReasons(status)
+	status.Live = len(status.NotLiveReasons) == 0
+	return status
+}
+
+// notLiveReasons lists what stands between the deployed commit and a running app
+func notLiveReasons(status StatusResponse) []string {
+	reasons := []string{}
+	deploy := status.Deploy
+	if deploy.Commit == "" {
+		reasons = append(reasons, "The repository has not been synced yet")
+	} else if deploy.CommitMatches != nil && !*deploy.CommitMatches {
+		reasons = append(reasons, fmt.Sprintf("Deployed commit is %s, not %s", shortCommit(deploy.Commit), deploy.RequestedCommit))
+	}
+
+	anyConfigured := false
+	for _, name := range controlJobs {
+		job := status.Jobs.Jobs[name]
+		anyConfigured = anyConfigured || job.Configured
+		if job.Status == "running" {
+			reasons = append(reasons, name+" job is running")
+		}
+	}
+	if anyConfigured && deploy.Commit != "" && status.Jobs.CompletedCommit != deploy.Commit {
+		if pre := status.Jobs.Jobs["PRE_DEPLOY"]; pre.Commit == deploy.Commit && (pre.Status == "failed" || pre.Status == "timeout") {
+			reasons = append(reasons, fmt.Sprintf("PRE_DEPLOY %s for the deployed commit", pre.Status))
+		} else {
+			reasons = append(reasons, "Deploy jobs have not completed for the deployed commit")
+		}
+	}
+
+	switch status.App.State {
+	case appRunning:
+	case appNotConfigured:
+		reasons = append(reasons, "No app is configured (set DEV_START_COMMAND or add dev_startup.sh)")
+	case appExited:
+		reasons = append(reasons, "The app process has exited")
+	default:
+		reasons = append(reasons, "The app is "+status.App.State)
+	}
+	return reasons
+}
+
+// readDeployStatus reads HEAD from the checkout, preferring the sync state
+// github-sync.sh writes after each cycle for the commit time
+func readDeployStatus(src configSource, requestedCommit string) DeployStatus {
+	var deploy DeployStatus
+	if state, err := readSyncState(); err == nil {
+		deploy.Commit, deploy.CommitTime = state.Commit, state.CommitTime
+	}
+	if commit, branch, err := readHead(filepath.Join(repoCheckoutPath(src), ".git")); err == nil {
+		if commit != deploy.Commit {
+			// A sync is mid-way; HEAD is what is on disk
+			deploy.CommitTime = ""
+		}
+		deploy.Commit, deploy.Branch = commit, branch
+	}
+
+	if requestedCommit != "" {
+		deploy.RequestedCommit = requestedCommit
+		matches := deploy.Commit != "" && strings.HasPrefix(deploy.Commit, strings.ToLower(requestedCommit))
+		deploy.CommitMatches = &matches
+	}
+	return deploy
+}
+
+// syncStateRecord is the counter file github-sync.sh rewrites after every sync cycle
+type syncStateRecord struct {
+	Attempts    int64  `json:"attempts"`
+	Failures    int64  `json:"failures"`
+	LastAttempt string `json:"last_attempt"`
+	LastSuccess string `json:"last_success"`
+	Commit      string `json:"commit"`
+	CommitTime  string `json:"commit_time"`
+}
+
+// readSyncState loads the counters written by github-sync.sh
+func readSyncState() (syncStateRecord, error) {
+	var state syncStateRecord
+	data, err := os.ReadFile(syncStateFile)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// readSyncStatus combines the sync counters, the sync loop's PID and the latest sync event
+func readSyncStatus() SyncStatus {
+	var status SyncStatus
+	if state, err := readSyncState(); err == nil {
+		status.Attempts, status.Failures = state.Attempts, state.Failures
+		status.LastAttempt, status.LastSuccess = state.LastAttempt, state.LastSuccess
+	} else if !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Error reading sync state: %v", err)
+	}
+	if pid, err := readPIDFile(syncPIDFile); err == nil {
+		status.Running = processRunning(pid)
+	}
+	status.LastEvent = activity.Last("sync_")
+	return status
+}
+
+// readJobsStatus reads each deploy job's state file from job-manager.sh
+func readJobsStatus() JobsStatus {
+	status := JobsStatus{Jobs: make(map[string]JobStatus)}
+	if data, err := os.ReadFile(lastJobCommitFile); err == nil {
+		status.CompletedCommit = strings.TrimSpace(string(data))
+	}
+	for _, name := range controlJobs {
+		job := JobStatus{Configured: os.Getenv(name+"_COMMAND") != ""}
+		if data, err := os.ReadFile(filepath.Join(jobStateDir, name+".json")); err == nil {
+			var state struct {
+				jobState
+				FinishedAt string `json:"finished_at"`
+			}
+			if err := json.Unmarshal(data, &state); err == nil {
+				job.Status, job.Commit, job.ExitCode = state.Status, state.Commit, state.ExitCode
+				job.StartedAt, job.FinishedAt, job.DurationSeconds = state.StartedAt, state.FinishedAt, state.DurationSeconds
+			}
+		}
+		status.Jobs[name] = job
+	}
+	return status
+}
+
+// readAppStatus reports the startup phase, the app's PID and, in proxy mode,
+// whether it answers on its port
+func readAppStatus() AppStatus {
+	status := AppStatus{Phase: readCurrentPhase(), Proxy: upstream != nil}
+	switch status.Phase {
+	case "no_app":
+		status.State = appNotConfigured
+		return status
+	case "app":
+	case "":
+		// Running outside startup.sh, so there is nothing to report on
+		status.State = appNotConfigured
+		status.Detail = "No startup phase recorded"
+		return status
+	default:
+		status.State = appStarting
+		status.Detail = "Container startup: " + status.Phase
+		return status
+	}
+
+	if pid, err := readPIDFile(appPIDFile); err == nil {
+		status.PID = pid
+		status.Running = processRunning(pid)
+	}
+	status.State = appRunning
+	if !status.Running && status.PID != 0 {
+		status.State = appExited
+	}
+	if upstream != nil {
+		up := upstream.isUp()
+		status.Port, status.Up = upstream.port, &up
+		if !up {
+			page := upstream.state(time.Now())
+			status.State, status.Detail = page.State, page.Detail
+		}
+	}
+	return status
+}
+
+// readHead resolves HEAD to a commit SHA and, when on a branch, its name
+func readHead(gitDir string) (commit, branch string, err error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", "", err
+	}
+
+	head := strings.TrimSpace(string(data))
+	ref, ok := strings.CutPrefix(head, "ref: ")
+	if !ok {
+		// Detached HEAD holds the SHA directly
+		return head, "", nil
+	}
+
+	branch = strings.TrimPrefix(ref, "refs/heads/")
+	commit, err = resolveRef(gitDir, ref)
+	return commit, branch, err
+}
+
+// resolveRef looks a ref up as a loose file first, then in packed-refs
+func resolveRef(gitDir, ref string) (string, error) {
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	file, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve %s", ref)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		if sha, name, ok := strings.Cut(line, " "); ok && name == ref {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("cannot resolve %s", ref)
+}
+
+// processRunning reports whether pid exists; signal 0 only checks
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// runtimes caches installedRuntimes; runtimes are fixed at image build time
+var runtimes struct {
+	once     sync.Once
+	versions map[string]string
+}
+
+// installedRuntimes returns the version of each language runtime on the PATH
+func installedRuntimes() map[string]string {
+	runtimes.once.Do(func() {
+		runtimes.versions = make(map[string]string)
+		for _, runtime := range runtimeCommands {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			output, err := exec.CommandContext(ctx, runtime.args[0], runtime.args[1:]...).Output()
+			cancel()
+			if err != nil {
+				continue
+			}
+			runtimes.versions[runtime.name] = runtimeVersion(string(output))
+		}
+	})
+	return runtimes.versions
+}
+
+// runtimeVersion picks the version number out of e.g. "Python 3.12.1" or "go1.23.4"
+func runtimeVersion(output string) string {
+	for _, field := range strings.Fields(output) {
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "go"), "v")
+		if field != "" && field[0] >= '0' && field[0] <= '9' {
+			return field
+		}
+	}
+	return strings.TrimSpace(output)
+}
+
+// statusHandler handles requests to the /api/v1/status endpoint
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/v1/status endpoint
+	if r.URL.Path != "/api/v1/status" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(buildStatus(r.URL.Query().Get("commit"))); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
@@ -0,0 +1,916 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+//go:generate sh bundle-xterm.sh
+
+// terminalUser is the account shells run as when the server itself runs as root
+const terminalUser = "devcontainer"
+
+// terminalTicketTTL is how long a new session waits for its WebSocket
+const terminalTicketTTL = 30 * time.Second
+
+// terminalPingInterval keeps idle sessions from being cut by the platform's load balancer
+const terminalPingInterval = 30 * time.Second
+
+// terminalHangupGrace is how long a shell gets to exit after SIGHUP before it is killed
+const terminalHangupGrace = 5 * time.Second
+
+// defaultTerminalRecordDir is where recordings go unless WEB_TERMINAL_RECORD_DIR is set
+const defaultTerminalRecordDir = "/tmp/dev-terminal-recordings"
+
+// Terminal session states
+const (
+	terminalPending = "pending"
+	terminalRunning = "running"
+)
+
+// TerminalSession describes a shell opened from the web terminal
+type TerminalSession struct {
+	ID        string `json:"id"`
+	State     string `json:"state"`
+	Created   string `json:"created"`
+	PID       int    `json:"pid,omitempty"`
+	Cols      uint16 `json:"cols"`
+	Rows      uint16 `json:"rows"`
+	Remote    string `json:"remote_addr"`
+	Recording string `json:"recording,omitempty"`
+
+	ticket  string
+	expires time.Time
+	hangup  chan struct{}
+}
+
+// TerminalTicket is the response to creating a session: the client opens
+// the WebSocket URL, which is valid once and for terminalTicketTTL
+type TerminalTicket struct {
+	ID        string `json:"id"`
+	WebSocket string `json:"websocket"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// terminalSessions tracks open and pending sessions
+type terminalSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*TerminalSession
+}
+
+// terminals holds every web terminal session of this server
+var terminals = &terminalSessions{sessions: make(map[string]*TerminalSession)}
+
+// terminalEnabled reports whether ENABLE_WEB_TERMINAL is on. The terminal also
+// needs WELCOME_CONTROL_TOKEN, the same token that guards the controls.
+func terminalEnabled() bool {
+	return getEnvOrDefault("ENABLE_WEB_TERMINAL", "false") == "true" && controlsEnabled()
+}
+
+// create reserves a session for the WebSocket that will carry it
+func (t *terminalSessions) create(remote string, cols, rows uint16) (*TerminalSession, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for id, session := range t.sessions {
+		if session.State == terminalPending && now.After(session.expires) {
+			delete(t.sessions, id)
+		}
+	}
+	if limit := getEnvInt("WEB_TERMINAL_MAX_SESSIONS", 4); len(t.sessions) >= limit {
+		return nil, fmt.Errorf("%d terminal sessions are already open (WEB_TERMINAL_MAX_SESSIONS)", limit)
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := randomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	session := &TerminalSession{
+		ID:      id,
+		State:   terminalPending,
+		Created: now.UTC().Format(time.RFC3339),
+		Cols:    cols,
+		Rows:    rows,
+		Remote:  remote,
+		ticket:  ticket,
+		expires: now.Add(terminalTicketTTL),
+		hangup:  make(chan struct{}),
+	}
+	t.sessions[id] = session
+	return session, nil
+}
+
+// claim exchanges a ticket for its session; each ticket works once
+func (t *terminalSessions) claim(ticket string) *TerminalSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, session := range t.sessions {
+		if session.State != terminalPending || time.Now().After(session.expires) {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(session.ticket), []byte(ticket)) == 1 {
+			session.State = terminalRunning
+			session.ticket = ""
+			return session
+		}
+	}
+	return nil
+}
+
+// list returns the sessions, oldest first
+func (t *terminalSessions) list() []TerminalSession {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	list := []TerminalSession{}
+	for _, session := range t.sessions {
+		if session.State == terminalPending && time.Now().After(session.expires) {
+			continue
+		}
+		list = append(list, *session)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created < list[j].Created })
+	return list
+}
+
+// update applies fn to a session under the lock
+func (t *terminalSessions) update(id string, fn func(*TerminalSession)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if session, ok := t.sessions[id]; ok {
+		fn(session)
+	}
+}
+
+// close hangs up a session's shell; it reports false for unknown sessions
+func (t *terminalSessions) close(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.sessions[id]
+	if !ok {
+		return false
+	}
+	if session.State == terminalPending {
+		delete(t.sessions, id)
+		return true
+	}
+	select {
+	case <-session.hangup:
+	default:
+		close(session.hangup)
+	}
+	return true
+}
+
+// remove forgets a session once its shell has exited
+func (t *terminalSessions) remove(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, id)
+}
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// terminalSize clamps a requested window size, defaulting to 80x24
+func terminalSize(cols, rows int) (uint16, uint16) {
+	clamp := func(value, fallback int) uint16 {
+		if value <= 0 {
+			return uint16(fallback)
+		}
+		return uint16(min(value, 1000))
+	}
+	return clamp(cols, 80), clamp(rows, 24)
+}
+
+// shellCommand builds the login shell for a session. When the server runs as
+// root (e.g. started by hand in the container), the shell drops to terminalUser.
+func shellCommand() *exec.Cmd {
+	cmd := exec.Command("bash", "--login")
+	env := []string{}
+	for _, entry := range os.Environ() {
+		// The shell can do anything the controls can, so it has no use for the token
+		if !strings.HasPrefix(entry, "WELCOME_CONTROL_TOKEN=") {
+			env = append(env, entry)
+		}
+	}
+	env = append(env, "TERM=xterm-256color", "COLORTERM=truecolor")
+
+	home, _ := os.UserHomeDir()
+	if os.Geteuid() == 0 {
+		if account, err := user.Lookup(terminalUser); err == nil {
+			uid, _ := strconv.ParseUint(account.Uid, 10, 32)
+			gid, _ := strconv.ParseUint(account.Gid, 10, 32)
+			cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}}
+			home = account.HomeDir
+			env = append(env, "HOME="+home, "USER="+terminalUser, "LOGNAME="+terminalUser, "SHELL=/bin/bash")
+		} else {
+			log.Printf("Warning: user %s not found, web terminal shells run as root", terminalUser)
+		}
+	}
+	cmd.Env = env
+
+	cmd.Dir = home
+	if workspace := projectWorkspace(); dirExists(workspace) {
+		cmd.Dir = workspace
+	}
+	return cmd
+}
+
+// run bridges a claimed session's WebSocket to a shell on a new PTY until
+// either side goes away
+func (s *TerminalSession) run(ws *wsConn) {
+	defer terminals.remove(s.ID)
+
+	cmd := shellCommand()
+	master, err := startPTY(cmd, s.Cols, s.Rows)
+	if err != nil {
+		log.Printf("Terminal session %s could not start a shell: %v", s.ID, err)
+		ws.Close(wsCloseInternalError, "could not start a shell: "+err.Error())
+		return
+	}
+	defer master.Close()
+	pid := cmd.Process.Pid
+
+	recorder := newTerminalRecorder(s)
+	defer recorder.Close()
+	terminals.update(s.ID, func(session *TerminalSession) {
+		session.PID = pid
+		session.Recording = recorder.Path()
+	})
+
+	log.Printf("Terminal session %s started (PID %d) from %s", s.ID, pid, s.Remote)
+	activity.Publish(ActivityEvent{Type: "terminal_opened", Level: levelInfo, Message: "A web terminal session was opened"})
+	started := time.Now()
+
+	exited := make(chan struct{})
+	var waitErr error
+	go func() {
+		waitErr = cmd.Wait()
+		close(exited)
+	}()
+
+	// Shell output goes to the browser as binary frames
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := master.Read(buf)
+			if n > 0 {
+				recorder.Output(buf[:n])
+				if ws.WriteMessage(wsBinary, buf[:n]) != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Keystrokes arrive as binary frames, control messages as JSON text frames
+	inputDone := make(chan struct{})
+	go func() {
+		defer close(inputDone)
+		for {
+			opcode, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			if opcode == wsBinary {
+				if _, err := master.Write(data); err != nil {
+					return
+				}
+				continue
+			}
+			s.handleControl(master, recorder, data)
+		}
+	}()
+
+	ping := time.NewTicker(terminalPingInterval)
+	defer ping.Stop()
+	reason := ""
+	for reason == "" {
+		select {
+		case <-ping.C:
+			ws.Ping()
+		case <-exited:
+			// Let the last output reach the browser before closing
+			select {
+			case <-outputDone:
+			case <-time.After(500 * time.Millisecond):
+			}
+			reason = "shell exited"
+			var exitErr *exec.ExitError
+			if errors.As(waitErr, &exitErr) {
+				reason = fmt.Sprintf("shell exited with code %d", exitErr.ExitCode())
+			}
+			ws.Close(wsCloseNormal, reason)
+		case <-inputDone:
+			reason = "browser disconnected"
+			hangupShell(pid, exited)
+		case <-s.hangup:
+			reason = "closed through the API"
+			ws.Close(wsCloseGoingAway, "session closed")
+			hangupShell(pid, exited)
+		}
+	}
+
+	duration := time.Since(started).Truncate(time.Second)
+	log.Printf("Terminal session %s ended after %s: %s", s.ID, duration, reason)
+	activity.Publish(ActivityEvent{Type: "terminal_closed", Level: levelInfo, Message: fmt.Sprintf("A web terminal session was closed after %s", duration)})
+}
+
+// handleControl applies a JSON control message: {"type":"resize","cols":..,"rows":..}
+// or {"type":"input","data":".."} for clients that cannot send binary frames
+func (s *TerminalSession) handleControl(master *os.File, recorder *terminalRecorder, data []byte) {
+	var message struct {
+		Type string `json:"type"`
+		Cols int    `json:"cols"`
+		Rows int    `json:"rows"`
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return
+	}
+	switch message.Type {
+	case "resize":
+		cols, rows := terminalSize(message.Cols, message.Rows)
+		if err := setPTYSize(master, cols, rows); err != nil {
+			log.Printf("Terminal session %s could not resize: %v", s.ID, err)
+			return
+		}
+		terminals.update(s.ID, func(session *TerminalSession) {
+			session.Cols, session.Rows = cols, rows
+		})
+		recorder.Resize(cols, rows)
+	case "input":
+		master.Write([]byte(message.Data))
+	}
+}
+
+// hangupShell sends SIGHUP to the shell's process group, as closing a real
+// terminal would, and kills whatever is left after terminalHangupGrace
+func hangupShell(pid int, exited <-chan struct{}) {
+	syscall.Kill(-pid, syscall.SIGHUP)
+	select {
+	case <-exited:
+	case <-time.After(terminalHangupGrace):
+		syscall.Kill(-pid, syscall.SIGKILL)
+		<-exited
+	}
+}
+
+// terminalRecorder writes a session's output as an asciicast v2 file
+// (https://docs.asciinema.org/manual/asciicast/v2/), playable with
+// "asciinema play". Keystrokes are not recorded, so typed passwords stay out.
+type terminalRecorder struct {
+	file    *os.File
+	started time.Time
+	partial []byte
+}
+
+// newTerminalRecorder starts a recording when WEB_TERMINAL_RECORD is true; the
+// recorder is a no-op otherwise or when the file cannot be created
+func newTerminalRecorder(s *TerminalSession) *terminalRecorder {
+	recorder := &terminalRecorder{started: time.Now()}
+	if getEnvOrDefault("WEB_TERMINAL_RECORD", "false") != "true" {
+		return recorder
+	}
+
+	dir := getEnvOrDefault("WEB_TERMINAL_RECORD_DIR", defaultTerminalRecordDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		log.Printf("Error creating terminal recording directory: %v", err)
+		return recorder
+	}
+	name := fmt.Sprintf("%s-%s.cast", recorder.started.UTC().Format("20060102-150405"), s.ID)
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Printf("Error creating terminal recording: %v", err)
+		return recorder
+	}
+	recorder.file = file
+
+	header, _ := json.Marshal(map[string]any{
+		"version":   2,
+		"width":     s.Cols,
+		"height":    s.Rows,
+		"timestamp": recorder.started.Unix(),
+		"title":     "web terminal session " + s.ID,
+		"env":       map[string]string{"SHELL": "/bin/bash", "TERM": "xterm-256color"},
+	})
+	recorder.write(header)
+	return recorder
+}
+
+// Path returns the recording file, or "" when not recording
+func (r *terminalRecorder) Path() string {
+	if r.file == nil {
+		return ""
+	}
+	return r.file.Name()
+}
+
+// Output records terminal output. The PTY can split a UTF-8 character across
+// reads, and each event must hold valid text, so an incomplete trailing
+// character is kept for the next call.
+func (r *terminalRecorder) Output(data []byte) {
+	if r.file == nil {
+		return
+	}
+	data = append(r.partial, data...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.partial = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		r.event("o", string(data[:cut]))
+	}
+}
+
+// Resize records a window size change
+func (r *terminalRecorder) Resize(cols, rows uint16) {
+	r.event("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// event appends one [time, code, data] line
+func (r *terminalRecorder) event(code, data string) {
+	if r.file == nil {
+		return
+	}
+	line, _ := json.Marshal([]any{time.Since(r.started).Seconds(), code, data})
+	r.write(line)
+}
+
+// write appends a line, stopping the recording if the disk fills up
+func (r *terminalRecorder) write(line []byte) {
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		log.Printf("Error writing terminal recording, stopping it: %v", err)
+		r.file.Close()
+		r.file = nil
+	}
+}
+
+// Close finishes the recording
+func (r *terminalRecorder) Close() {
+	if r.file != nil {
+		r.file.Close()
+	}
+}
+
+// checkTerminalEnabled answers 403 when the web terminal is off
+func checkTerminalEnabled(w http.ResponseWriter) bool {
+	if !terminalEnabled() {
+		http.Error(w, "The web terminal is disabled; set ENABLE_WEB_TERMINAL=true and WELCOME_CONTROL_TOKEN to enable it", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// terminalSessionsHandler lists sessions (GET) and creates one (POST). A new
+// session returns a one-time WebSocket URL, since browsers cannot send an
+// Authorization header when opening a WebSocket.
+func terminalSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /api/terminal/sessions endpoint
+	if r.URL.Path != "/api/terminal/sessions" {
+		http.NotFound(w, r)
+		return
+	}
+	if !checkTerminalEnabled(w) {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		if !checkControlToken(w, r) {
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeControlJSON(w, http.StatusOK, terminals.list())
+		return
+	}
+	if !authorizeControl(w, r) {
+		return
+	}
+
+	var request struct {
+		Cols int `json:"cols"`
+		Rows int `json:"rows"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	cols, rows := terminalSize(request.Cols, request.Rows)
+	session, err := terminals.create(r.RemoteAddr, cols, rows)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeControlJSON(w, http.StatusCreated, TerminalTicket{
+		ID:        session.ID,
+		WebSocket: "terminal/ws?ticket=" + session.ticket,
+		ExpiresAt: session.expires.UTC().Format(time.RFC3339),
+	})
+}
+
+// terminalSessionHandler closes a session on DELETE /api/terminal/sessions/<id>
+func terminalSessionHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/terminal/sessions/")
+	if !checkTerminalEnabled(w) {
+		return
+	}
+	if r.Method != http.MethodDelete {
+		w.Header().Set("Allow", http.MethodDelete)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !checkControlToken(w, r) {
+		return
+	}
+	if !terminals.close(id) {
+		http.NotFound(w, r)
+		return
+	}
+	writeControlJSON(w, http.StatusOK, ControlResponse{Status: "closed", Message: "The session's shell was sent SIGHUP"})
+}
+
+// terminalSocketHandler upgrades /terminal/ws?ticket=... and runs the session
+func terminalSocketHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /terminal/ws endpoint
+	if r.URL.Path != "/terminal/ws" {
+		http.NotFound(w, r)
+		return
+	}
+	if !checkTerminalEnabled(w) {
+		return
+	}
+	session := terminals.claim(r.URL.Query().Get("ticket"))
+	if session == nil {
+		http.Error(w, "Invalid or expired terminal ticket; create a new session", http.StatusUnauthorized)
+		return
+	}
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		terminals.remove(session.ID)
+		return
+	}
+	session.run(ws)
+}
+
+// TerminalPageData holds data for the terminal page template
+type TerminalPageData struct {
+	Enabled   bool
+	Recording bool
+}
+
+// terminalPageHandler serves the xterm.js page; it holds no secrets, the
+// token is entered in the browser
+func terminalPageHandler(w http.ResponseWriter, r *http.Request) {
+	// Only respond to /terminal endpoint
+	if r.URL.Path != "/terminal" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := TerminalPageData{
+		Enabled:   terminalEnabled(),
+		Recording: getEnvOrDefault("WEB_TERMINAL_RECORD", "false") == "true",
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if err := terminalPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Error executing template: %v", err)
+	}
+}
+
+// terminalAssets holds xterm.js and its fit addon at pinned versions, copied
+// into terminal-assets/ by bundle-xterm.sh before the build so the terminal
+// loads no script from a third-party host
+//
+//go:embed all:terminal-assets
+var terminalAssets embed.FS
+
+// terminalAssetFiles are the files bundle-xterm.sh puts in terminal-assets/
+var terminalAssetFiles = map[string]bool{"xterm.js": true, "xterm.css": true, "addon-fit.js": true}
+
+// terminalAssetHandler serves the bundled xterm.js files under /terminal/assets/
+func terminalAssetHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/terminal/assets/")
+	if !terminalAssetFiles[name] {
+		http.NotFound(w, r)
+		return
+	}
+	// Assets are fixed for the life of the binary
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeFileFS(w, r, terminalAssets, "terminal-assets/"+name)
+}
+
+// terminalPageTemplate is the web terminal
+var terminalPageTemplate = template.Must(template.New("terminal").Parse(terminalPageHTML))
+
+// terminalPageHTML is the HTML template for the web terminal; xterm.js is
+// served from the bundled assets
+const terminalPageHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Web Terminal</title>
+    <link rel="stylesheet" href="terminal/assets/xterm.css">
+    <style>
+        * {
+            margin: 0;
+            padding: 0;
+            box-sizing: border-box;
+        }
+        html, body {
+            height: 100%;
+        }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif;
+            background: #1e1e1e;
+            color: #d4d4d4;
+            display: flex;
+            flex-direction: column;
+        }
+        a {
+            color: #9cdcfe;
+        }
+        .toolbar {
+            display: flex;
+            flex-wrap: wrap;
+            align-items: center;
+            gap: 8px;
+            padding: 8px 12px;
+            background: #252526;
+            border-bottom: 1px solid #333;
+        }
+        .toolbar h1 {
+            font-size: 1em;
+            margin-right: 8px;
+        }
+        .toolbar input, .toolbar button {
+            background: #3c3c3c;
+            color: #d4d4d4;
+            border: 1px solid #555;
+            border-radius: 4px;
+            padding: 5px 10px;
+            font-size: 0.9em;
+        }
+        .toolbar button {
+            cursor: pointer;
+        }
+        .toolbar button:hover {
+            background: #505050;
+        }
+        .tabs {
+            display: flex;
+            gap: 4px;
+        }
+        .tab {
+            display: flex;
+            align-items: center;
+            gap: 6px;
+        }
+        .tab.active {
+            background: #0e639c;
+            border-color: #1177bb;
+        }
+        .tab .close {
+            opacity: 0.7;
+        }
+        .hint {
+            color: #888;
+            font-size: 0.85em;
+        }
+        .error {
+            color: #f48771;
+            font-size: 0.85em;
+        }
+        .notice {
+            max-width: 640px;
+            margin: 40px auto;
+            line-height: 1.6;
+        }
+        .notice code {
+            background: #333;
+            padding: 2px 6px;
+            border-radius: 3px;
+        }
+        #terminals {
+            flex: 1;
+            position: relative;
+            min-height: 0;
+        }
+        .terminal-pane {
+            position: absolute;
+            inset: 0;
+            padding: 6px;
+        }
+    </style>
+</head>
+<body>
+    {{if .Enabled}}
+    <div class="toolbar">
+        <h1>💻 Web Terminal</h1>
+        <input type="password" id="token" placeholder="WELCOME_CONTROL_TOKEN" autocomplete="off">
+        <button type="button" id="new-session">+ New session</button>
+        <div class="tabs" id="tabs"></div>
+        <span class="error" id="error"></span>
+        <span class="hint" style="margin-left: auto;">{{if .Recording}}Sessions are recorded · {{end}}<a href="./">welcome page</a></span>
+    </div>
+    <div id="terminals"></div>
+
+    <script src="terminal/assets/xterm.js"></script>
+    <script src="terminal/assets/addon-fit.js"></script>
+    <script>
+        (function() {
+            const tokenInput = document.getElementById('token');
+            const tabsEl = document.getElementById('tabs');
+            const panesEl = document.getElementById('terminals');
+            const errorEl = document.getElementById('error');
+            const encoder = new TextEncoder();
+            const sessions = [];
+            let active = null;
+            let count = 0;
+
+            tokenInput.value = localStorage.getItem('welcomeControlToken') || '';
+            tokenInput.addEventListener('change', function() {
+                localStorage.setItem('welcomeControlToken', tokenInput.value);
+            });
+
+            function activate(session) {
+                active = session;
+                sessions.forEach(function(s) {
+                    s.pane.hidden = s !== session;
+                    s.tab.classList.toggle('active', s === session);
+                });
+                session.fit.fit();
+                session.term.focus();
+            }
+
+            function remove(session) {
+                const index = sessions.indexOf(session);
+                if (index < 0) {
+                    return;
+                }
+                sessions.splice(index, 1);
+                session.ws.close();
+                session.term.dispose();
+                session.pane.remove();
+                session.tab.remove();
+                if (active === session && sessions.length > 0) {
+                    activate(sessions[sessions.length - 1]);
+                }
+            }
+
+            function sendResize(session) {
+                if (session.ws.readyState === WebSocket.OPEN) {
+                    session.ws.send(JSON.stringify({type: 'resize', cols: session.term.cols, rows: session.term.rows}));
+                }
+            }
+
+            async function openSession() {
+                errorEl.textContent = '';
+                if (typeof Terminal === 'undefined' || typeof FitAddon === 'undefined') {
+                    errorEl.textContent = 'xterm.js is not bundled; run bundle-xterm.sh (go generate) and rebuild the server';
+                    return;
+                }
+                const pane = document.createElement('div');
+                pane.className = 'terminal-pane';
+                panesEl.appendChild(pane);
+                const term = new Terminal({cursorBlink: true, fontSize: 14, scrollback: 5000, theme: {background: '#1e1e1e'}});
+                const fit = new FitAddon.FitAddon();
+                term.loadAddon(fit);
+                term.open(pane);
+                fit.fit();
+
+                let ticket;
+                try {
+                    const response = await fetch('api/terminal/sessions', {
+                        method: 'POST',
+                        headers: {'Authorization': 'Bearer ' + tokenInput.value, 'Content-Type': 'application/json'},
+                        body: JSON.stringify({cols: term.cols, rows: term.rows})
+                    });
+                    if (!response.ok) {
+                        throw new Error((await response.text()).trim() || response.statusText);
+                    }
+                    ticket = await response.json();
+                } catch (err) {
+                    errorEl.textContent = err.message;
+                    term.dispose();
+                    pane.remove();
+                    return;
+                }
+
+                const url = new URL(ticket.websocket, window.location.href);
+                url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+                const ws = new WebSocket(url);
+                ws.binaryType = 'arraybuffer';
+
+                const tab = document.createElement('button');
+                tab.type = 'button';
+                tab.className = 'tab';
+                tab.innerHTML = '<span></span><span class="close" title="Close session">✕</span>';
+                tab.firstChild.textContent = 'Session ' + (++count);
+                tabsEl.appendChild(tab);
+
+                const session = {id: ticket.id, term: term, fit: fit, ws: ws, pane: pane, tab: tab};
+                sessions.push(session);
+                tab.addEventListener('click', function(event) {
+                    if (event.target.classList.contains('close')) {
+                        remove(session);
+                    } else {
+                        activate(session);
+                    }
+                });
+
+                ws.onopen = function() {
+                    sendResize(session);
+                };
+                ws.onmessage = function(event) {
+                    term.write(new Uint8Array(event.data));
+                };
+                ws.onclose = function(event) {
+                    term.write('\r\n\x1b[90m[' + (event.reason || 'connection closed') + ']\x1b[0m\r\n');
+                    tab.firstChild.textContent += ' (ended)';
+                };
+                term.onData(function(data) {
+                    if (ws.readyState === WebSocket.OPEN) {
+                        ws.send(encoder.encode(data));
+                    }
+                });
+                term.onResize(function() {
+                    sendResize(session);
+                });
+                activate(session);
+            }
+
+            document.getElementById('new-session').addEventListener('click', openSession);
+            window.addEventListener('resize', function() {
+                if (active) {
+                    active.fit.fit();
+                }
+            });
+            if (tokenInput.value) {
+                openSession();
+            } else {
+                tokenInput.focus();
+            }
+        })();
+    </script>
+    {{else}}
+    <div class="notice">
+        <h1>💻 Web Terminal</h1>
+        <p>The web terminal is disabled. It opens a shell in the dev container from the browser, so it is off unless both of these are set:</p>
+        <ul style="margin: 10px 0 10px 20px;">
+            <li><code>ENABLE_WEB_TERMINAL=true</code></li>
+            <li><code>WELCOME_CONTROL_TOKEN</code> (as a secret), which the page asks for</li>
+        </ul>
+        <p>Until then, use <code>doctl apps console</code>. <a href="./">Back to the welcome page</a></p>
+    </div>
+    {{end}}
+</body>
+</html>
+`
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocketGUID is the fixed key suffix from RFC 6455 section 1.3
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessage caps a single client message; terminal input is small
+const maxWebSocketMessage = 1 << 20
+
+// WebSocket opcodes
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// WebSocket close codes
+const (
+	wsCloseNormal        = 1000
+	wsCloseGoingAway     = 1001
+	wsCloseProtocolError = 1002
+	wsCloseTooBig        = 1009
+	wsCloseInternalError = 1011
+)
+
+// errWebSocketClosed is returned by ReadMessage once the client closed the connection
+var errWebSocketClosed = errors.New("websocket closed")
+
+// wsConn is a server-side WebSocket connection. The standard library has no
+// WebSocket support and the server stays dependency free, so this implements
+// the small part of RFC 6455 the terminal needs: no extensions or subprotocols.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+// upgradeWebSocket completes the opening handshake and takes over the connection
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected a WebSocket upgrade request", http.StatusBadRequest)
+		return nil, errors.New("not a websocket upgrade")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "Invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("invalid websocket key")
+	}
+
+	conn, buffered, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "WebSocket upgrade is not supported here", http.StatusInternalServerError)
+		return nil, err
+	}
+	// The server's read and write timeouts would otherwise end the session
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n"
+	if _, err := buffered.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := buffered.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, reader: buffered.Reader}, nil
+}
+
+// headerContainsToken reports whether a comma-separated header holds token
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage returns the next text or binary message. Pings are answered
+// and fragmented messages reassembled; a close frame is echoed and reported
+// as errWebSocketClosed.
+func (c *wsConn) ReadMessage() (int, []byte, error) {
+	var (
+		opcode  int
+		message []byte
+	)
+	for {
+		fin, frameOpcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOpcode {
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			code := wsCloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.Close(code, "")
+			return 0, nil, errWebSocketClosed
+		case wsText, wsBinary:
+			if opcode != 0 {
+				return 0, nil, c.fail(wsCloseProtocolError, "new message before the previous one finished")
+			}
+			opcode = frameOpcode
+		case wsContinuation:
+			if opcode == 0 {
+				return 0, nil, c.fail(wsCloseProtocolError, "continuation without a message")
+			}
+		default:
+			return 0, nil, c.fail(wsCloseProtocolError, fmt.Sprintf("unknown opcode %d", frameOpcode))
+		}
+
+		if len(message)+len(payload) > maxWebSocketMessage {
+			return 0, nil, c.fail(wsCloseTooBig, "message too big")
+		}
+		message = append(message, payload...)
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrame reads and unmasks one frame
+func (c *wsConn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(wsCloseProtocolError, "reserved bits set")
+	}
+	// Browsers must mask every frame they send
+	if header[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(wsCloseProtocolError, "unmasked client frame")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opcode >= wsClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(wsCloseProtocolError, "invalid control frame")
+	}
+	if length > maxWebSocketMessage {
+		return false, 0, nil, c.fail(wsCloseTooBig, "message too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends data as a single text or binary frame
+func (c *wsConn) WriteMessage(opcode int, data []byte) error {
+	return c.writeFrame(opcode, data)
+}
+
+// Ping sends a ping so proxies and load balancers see traffic on idle sessions
+func (c *wsConn) Ping() error {
+	return c.writeFrame(wsPing, nil)
+}
+
+// writeFrame sends one unmasked frame; server frames are never masked
+func (c *wsConn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return errWebSocketClosed
+	}
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | byte(opcode)
+	switch length := len(payload); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close sends a close frame with code and reason, then closes the connection.
+// It is safe to call more than once.
+func (c *wsConn) Close(code int, reason string) {
+	// A close reason must fit in a control frame
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.conn.Write(append([]byte{0x80 | wsClose, byte(len(payload))}, payload...))
+	c.conn.Close()
+}
+
+// fail closes the connection after a protocol violation
+func (c *wsConn) fail(code int, reason string) error {
+	c.Close(code, reason)
+	return fmt.Errorf("websocket: %s", reason)
+}